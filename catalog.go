@@ -0,0 +1,707 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+)
+
+// 返信などに使う言語
+const (
+	langJa      = "ja"
+	langEn      = "en"
+	defaultLang = langJa
+)
+
+// 対応している言語
+var supportedLangs = []string{langJa, langEn}
+
+// メッセージカタログ（キーごとに言語別の文字列を並べる。引数はfmt.Sprintfの書式で埋め込む）
+//
+// 英語の訳がなければ日本語を使う。"usage."で始まるキーはusage.htmlに埋め込むHTML
+var catalog = map[string]map[string]string{
+	// 友だち追加、招待時の初期設定
+	"onboarding.welcome": {
+		langJa: "リマインダを登録しました！\nこのBOTは、調整さんの出欠状況を開催前にお知らせします。使いかたはこちらのページをご覧ください\n%s\n\nFor English, type /set lang en",
+		langEn: "The reminder has been registered!\nThis BOT posts the attendance of your chouseisan event before it takes place. See this page for how to use it\n%s",
+	},
+	"onboarding.ask_url": {
+		langJa: "まずは、リマインドする調整さんイベントのURL（https://chouseisan.com/s?h=...）を、このトークに送ってください",
+		langEn: "First, send the URL of the chouseisan event to remind (https://chouseisan.com/s?h=...) to this chat",
+	},
+	"onboarding.pasted_new": {
+		langJa: "調整さんのURLが貼られました。リマインドする調整さんイベントに設定しますか？\n%s",
+		langEn: "A chouseisan URL was posted. Do you want to remind this event?\n%s",
+	},
+	"onboarding.pasted_change": {
+		langJa: "調整さんのURLが貼られました。リマインドする調整さんイベントを変更しますか？\n%s",
+		langEn: "A chouseisan URL was posted. Do you want to switch the event to remind?\n%s",
+	},
+	"onboarding.pasted_set": {
+		langJa: "設定する",
+		langEn: "Set",
+	},
+	"onboarding.pasted_cancel": {
+		langJa: "キャンセル",
+		langEn: "Cancel",
+	},
+	"onboarding.confirm": {
+		langJa: "調整さんイベント%sをリマインドします",
+		langEn: "The chouseisan event %s will be reminded",
+	},
+	"onboarding.timing": {
+		langJa: "開催%d日前と当日の%d:00（%s）に、出欠状況をお知らせします。時刻を変えるときは、下のボタンから選んでください",
+		langEn: "The attendance will be posted %d days before and on the day at %d:00 (%s). To change the time, choose one from the buttons below",
+	},
+
+	"join.restored": {
+		langJa: "おかえりなさい！以前の設定を引き継ぎました",
+		langEn: "Welcome back! Your previous settings have been restored",
+	},
+	"member.joined": {
+		langJa: "ようこそ！このグループの調整さんはこちらです。出欠を入力してください\n%s\n\n「/iam 調整さんでの名前」で名前を登録すると、出欠が未入力のときにメンションでお知らせします",
+		langEn: "Welcome! Here is the chouseisan event of this group. Please enter your attendance\n%s\n\nRegister your name with \"/iam NAME_ON_CHOUSEISAN\" to be mentioned when you have not answered",
+	},
+	"member.left": {
+		langJa: "%s さんがグループを退出しました。調整さんの出欠表から削除する場合は、調整さんのページで編集してください\n%s",
+		langEn: "%s left the group. To remove them from the attendance table, edit the chouseisan event\n%s",
+	},
+
+	// コマンド共通
+	"command.invalid": {
+		langJa: "無効なコマンドです。\n有効なコマンドは、こちらのページをご覧ください\n%s",
+		langEn: "Invalid command.\nSee this page for available commands\n%s",
+	},
+	"command.no_event": {
+		langJa: "調整さんイベントが設定されていません（/set chouseisan で設定してください）",
+		langEn: "No chouseisan event is set (set one with /set chouseisan)",
+	},
+	"command.fetch_failed": {
+		langJa: "調整さんの出欠表を取得できませんでした。時間をおいて再度お試しください",
+		langEn: "Could not fetch the chouseisan attendance table. Please try again later",
+	},
+
+	// `set chouseisan`
+	"set_chouseisan.failed": {
+		langJa: "調整さんイベントの設定に失敗しました",
+		langEn: "Failed to set the chouseisan event",
+	},
+	"set_chouseisan.fetch_failed": {
+		langJa: "調整さんイベントを取得できなかったため、設定しませんでした。時間をおいて再度お試しください\n%s",
+		langEn: "The chouseisan event was not set because it could not be fetched. Please try again later\n%s",
+	},
+	"set_chouseisan.not_found": {
+		langJa: "調整さんイベントが見つからなかったため、設定しませんでした。URLが正しいか、イベントが削除されていないか確認してください\n%s",
+		langEn: "The chouseisan event was not set because it was not found. Please check the URL, or whether the event has been deleted\n%s",
+	},
+	"set_chouseisan.unparsable": {
+		langJa: "調整さんイベントの出欠表を読み取れなかったため、設定しませんでした。候補日程が登録されているか確認してください\n%s",
+		langEn: "The chouseisan event was not set because its attendance table could not be read. Please check that it has candidate dates\n%s",
+	},
+	"set_chouseisan.event": {
+		langJa: "「%s」（候補日程%d件、メンバー%d名）",
+		langEn: "\"%s\" (%d candidate dates, %d members)",
+	},
+	"set_chouseisan.done": {
+		langJa: "リマインドする調整さんイベントを%sに設定しました",
+		langEn: "The chouseisan event to remind has been set to %s",
+	},
+
+	// `set name`
+	"set_name.failed": {
+		langJa: "グループ（もしくはトークルーム）の名前の設定に失敗しました",
+		langEn: "Failed to set the group (or room) name",
+	},
+	"set_name.done": {
+		langJa: "グループ（もしくはトークルーム）の名前を設定しました",
+		langEn: "The group (or room) name has been set",
+	},
+	"set_name.auto": {
+		langJa: "グループの名前を、LINEのグループ名から自動で更新するように設定しました",
+		langEn: "The group name will be updated automatically from the LINE group name",
+	},
+
+	// `set quiet`
+	"set_quiet.failed": {
+		langJa: "リマインドを送信しない時間帯の設定に失敗しました",
+		langEn: "Failed to set the quiet hours",
+	},
+	"set_quiet.off": {
+		langJa: "リマインドを送信しない時間帯を解除しました",
+		langEn: "The quiet hours have been cleared",
+	},
+	"set_quiet.done": {
+		langJa: "リマインドを送信しない時間帯を%d:00〜%d:00に設定しました",
+		langEn: "Reminders will not be sent from %d:00 to %d:00",
+	},
+
+	// `set timezone`
+	"set_timezone.failed": {
+		langJa: "タイムゾーンの設定に失敗しました（Asia/Tokyo、Europe/Berlinのように指定してください）",
+		langEn: "Failed to set the time zone (specify it like Asia/Tokyo or Europe/Berlin)",
+	},
+	"set_timezone.done": {
+		langJa: "タイムゾーンを%sに設定しました",
+		langEn: "The time zone has been set to %s",
+	},
+
+	// `set holiday`
+	"set_holiday.failed": {
+		langJa: "土日祝日のリマインド方針の設定に失敗しました",
+		langEn: "Failed to set the holiday policy",
+	},
+	"set_holiday.send": {
+		langJa: "土日祝日もリマインドするように設定しました",
+		langEn: "Reminders will be sent on weekends and holidays too",
+	},
+	"set_holiday.skip": {
+		langJa: "土日祝日はリマインドしないように設定しました",
+		langEn: "Reminders will not be sent on weekends and holidays",
+	},
+	"set_holiday.shift": {
+		langJa: "土日祝日のリマインドを直前の平日に前倒しするように設定しました",
+		langEn: "Reminders on weekends and holidays will be sent on the previous weekday",
+	},
+
+	// `set deadline`
+	"set_deadline.failed": {
+		langJa: "回答期限の設定に失敗しました",
+		langEn: "Failed to set the answer deadline",
+	},
+	"set_deadline.off": {
+		langJa: "回答期限を解除しました",
+		langEn: "The answer deadline has been cleared",
+	},
+	"set_deadline.done": {
+		langJa: "回答期限を%sに設定しました。%sに、まだ出欠を入力していないメンバーをお知らせします",
+		langEn: "The answer deadline has been set to %s. Members who have not answered yet will be notified %s",
+	},
+	"set_deadline.before": {
+		langJa: "%d日前",
+		langEn: "%d day(s) before",
+	},
+	"set_deadline.same_day": {
+		langJa: "期限当日",
+		langEn: "on the deadline day",
+	},
+
+	// `set notify changes`
+	"set_notify.failed": {
+		langJa: "出欠変更の通知の設定に失敗しました",
+		langEn: "Failed to set the change notification",
+	},
+	"set_notify.on": {
+		langJa: "リマインドした日程の出欠が変更されたら、お知らせするように設定しました",
+		langEn: "You will be notified when answers for reminded dates change",
+	},
+	"set_notify.off": {
+		langJa: "出欠変更の通知を停止しました",
+		langEn: "The change notification has been stopped",
+	},
+
+	// `set remindtime`
+	"set_remindtime.choose": {
+		langJa: "リマインドする時刻を選んでください（一覧にない時刻は「/set remindtime 23」のように入力してください）",
+		langEn: "Choose the reminder time (for other hours, type like \"/set remindtime 23\")",
+	},
+	"set_remindtime.failed": {
+		langJa: "リマインド時刻の設定に失敗しました",
+		langEn: "Failed to set the reminder time",
+	},
+	"set_remindtime.done": {
+		langJa: "リマインド時刻を%d:00に設定しました",
+		langEn: "The reminder time has been set to %d:00",
+	},
+
+	// `set template`、`preview template`
+	"set_template.failed": {
+		langJa: "リマインドのテンプレートの設定に失敗しました",
+		langEn: "Failed to set the reminder template",
+	},
+	"set_template.default": {
+		langJa: "リマインドをデフォルトのカード形式に戻しました",
+		langEn: "Reminders have been reset to the default card",
+	},
+	"set_template.done": {
+		langJa: "リマインドのテンプレートを設定しました（/preview template で確認できます）",
+		langEn: "The reminder template has been set (check it with /preview template)",
+	},
+	"template.too_long": {
+		langJa: "テンプレートが長すぎます",
+		langEn: "The template is too long",
+	},
+	"template.call": {
+		langJa: "{{template}}は使えません",
+		langEn: "{{template}} is not allowed",
+	},
+	"template.define": {
+		langJa: "{{define}}、{{block}}は使えません",
+		langEn: "{{define}} and {{block}} are not allowed",
+	},
+	"template.too_deep": {
+		langJa: "{{range}}、{{with}}の入れ子は2段までです",
+		langEn: "{{range}} and {{with}} can be nested up to 2 levels",
+	},
+	"template.output_too_long": {
+		langJa: "出力が長すぎます",
+		langEn: "The output is too long",
+	},
+	"template.output_empty": {
+		langJa: "出力が空です",
+		langEn: "The output is empty",
+	},
+	"preview_template.failed": {
+		langJa: "テンプレートの取得に失敗しました",
+		langEn: "Failed to get the template",
+	},
+	"preview_template.none": {
+		langJa: "テンプレートは設定されていません（デフォルトのカード形式でリマインドします）",
+		langEn: "No template is set (reminders are sent as the default card)",
+	},
+	"preview_template.error": {
+		langJa: "テンプレートを適用できませんでした（デフォルトのカード形式でリマインドします）",
+		langEn: "Could not apply the template (reminders are sent as the default card)",
+	},
+
+	// `set lang`
+	"set_lang.failed": {
+		langJa: "言語の設定に失敗しました",
+		langEn: "Failed to set the language",
+	},
+	"set_lang.done": {
+		langJa: "言語を日本語に設定しました",
+		langEn: "The language has been set to English",
+	},
+
+	// `show settings`
+	"show_settings.failed": {
+		langJa: "設定の取得に失敗しました",
+		langEn: "Failed to get the settings",
+	},
+	"settings.message": {
+		langJa: "現在の設定\n\nグループ名: %s\n調整さんイベント: %s\nリマインド: 3日前と当日の%d:00（%s）\n送信しない時間帯: %s\n土日祝日: %s\n回答期限: %s\n出欠変更の通知: %s",
+		langEn: "Current settings\n\nGroup name: %s\nChouseisan event: %s\nReminder: 3 days before and on the day at %d:00 (%s)\nQuiet hours: %s\nWeekends and holidays: %s\nAnswer deadline: %s\nChange notification: %s",
+	},
+	"settings.event_none": {
+		langJa: "未設定（/set chouseisan で設定してください）",
+		langEn: "not set (set one with /set chouseisan)",
+	},
+	"settings.none": {
+		langJa: "なし",
+		langEn: "none",
+	},
+	"settings.holiday_send": {
+		langJa: "送信する",
+		langEn: "send",
+	},
+	"settings.holiday_skip": {
+		langJa: "送信しない",
+		langEn: "skip",
+	},
+	"settings.holiday_shift": {
+		langJa: "直前の平日に前倒し",
+		langEn: "move to the previous weekday",
+	},
+	"settings.deadline": {
+		langJa: "%s（%d日前にお知らせ）",
+		langEn: "%s (notify %d day(s) before)",
+	},
+
+	// `remind now`、`show schedule`
+	"remind_now.failed": {
+		langJa: "出欠状況の取得に失敗しました",
+		langEn: "Failed to get the attendance",
+	},
+	"remind_now.none": {
+		langJa: "3日後までの予定はありません",
+		langEn: "No events in the next 3 days",
+	},
+	"show_schedule.failed": {
+		langJa: "日程の取得に失敗しました",
+		langEn: "Failed to get the schedule",
+	},
+	"schedule_list.title": {
+		langJa: "日程一覧",
+		langEn: "Schedule",
+	},
+	"schedule_list.none": {
+		langJa: "今後の予定はありません",
+		langEn: "No upcoming events",
+	},
+	"schedule_list.row": {
+		langJa: "%s ○%d ×%d 不明/未入力%d",
+		langEn: "%s ○%d ×%d undecided/no answer %d",
+	},
+
+	// リマインドのサマリ
+	"summary.body": {
+		langJa: "%sの出欠状況をお知らせします\n\n参加: %d名%s\n不参加: %d名\n不明/未入力: %d名%s",
+		langEn: "Attendance for %s\n\nAttending: %d%s\nNot attending: %d\nUndecided/no answer: %d%s",
+	},
+	"summary.footer": {
+		langJa: "\n\n詳細および出欠変更は「調整さん」へ\n%s",
+		langEn: "\n\nSee \"chouseisan\" for details or to change your answer\n%s",
+	},
+
+	// リマインドのカード
+	"flex.present": {
+		langJa: "○ 参加",
+		langEn: "○ Attending",
+	},
+	"flex.maybe": {
+		langJa: "△ 未定",
+		langEn: "△ Maybe",
+	},
+	"flex.absent": {
+		langJa: "× 不参加",
+		langEn: "× Not attending",
+	},
+	"flex.unanswered": {
+		langJa: "未入力",
+		langEn: "No answer",
+	},
+	"flex.count": {
+		langJa: "%d名",
+		langEn: "%d",
+	},
+	"flex.open": {
+		langJa: "出欠を登録（変更）する",
+		langEn: "Answer (or change)",
+	},
+
+	// 未入力メンバーへのメンション
+	"mention.unanswered": {
+		langJa: "%sの出欠がまだ入力されていません",
+		langEn: "Attendance for %s has not been entered yet",
+	},
+	"mention.names": {
+		langJa: "%sさん",
+		langEn: "%s",
+	},
+	"mention.request": {
+		langJa: "出欠の入力をお願いします",
+		langEn: "Please enter your attendance",
+	},
+
+	// 回答期限の未回答リマインド、個別通知、出欠変更の通知
+	"deadline.before": {
+		langJa: "%d/%dの回答期限まであと%d日です",
+		langEn: "%[3]d day(s) left until the answer deadline %[1]d/%[2]d",
+	},
+	"deadline.today": {
+		langJa: "%d/%dの回答期限は今日です",
+		langEn: "The answer deadline %d/%d is today",
+	},
+	"deadline.unanswered": {
+		langJa: "まだ出欠を入力していないメンバー: %d名(%s)",
+		langEn: "Members who have not answered yet: %d (%s)",
+	},
+	"deadline.enter": {
+		langJa: "出欠の入力は「調整さん」へ\n%s",
+		langEn: "Enter your attendance on chouseisan\n%s",
+	},
+	"personal.deadline": {
+		langJa: "「%s」の調整さんイベントの回答期限は%d/%dです",
+		langEn: "The answer deadline of the chouseisan event of \"%s\" is %d/%d",
+	},
+	"personal.pending": {
+		langJa: "%sさんの出欠が未入力/△の日程:",
+		langEn: "Dates %s has not answered or answered △:",
+	},
+	"changes.title": {
+		langJa: "出欠が変更されました",
+		langEn: "Answers have been changed",
+	},
+	"changes.change": {
+		langJa: "変更: %s %s→%s",
+		langEn: "Changed: %s %s→%s",
+	},
+	"changes.detail": {
+		langJa: "詳細は「調整さん」へ\n%s",
+		langEn: "See chouseisan for details\n%s",
+	},
+	"answer.unanswered": {
+		langJa: "未入力",
+		langEn: "no answer",
+	},
+
+	// `help`、クイックリプライ
+	"help.message": {
+		langJa: "ボタンから操作を選ぶか、コマンドを入力してください\n使いかたはこちらのページをご覧ください\n%s",
+		langEn: "Choose an action from the buttons or type a command\nSee this page for how to use it\n%s",
+	},
+	"menu.remind_now": {
+		langJa: "今すぐ集計",
+		langEn: "Attendance now",
+	},
+	"menu.show_settings": {
+		langJa: "設定を確認",
+		langEn: "Show settings",
+	},
+	"menu.set_remindtime": {
+		langJa: "リマインド時刻を変更",
+		langEn: "Change remind time",
+	},
+
+	// `iam`、`whois`、`subscribe me`
+	"iam.no_user": {
+		langJa: "LINEのユーザを特定できないため、名前を登録できませんでした",
+		langEn: "Could not identify your LINE user, so the name was not registered",
+	},
+	"iam.not_found": {
+		langJa: "調整さんの出欠表に「%s」さんが見つかりませんでした",
+		langEn: "\"%s\" was not found in the chouseisan attendance table",
+	},
+	"iam.suggest": {
+		langJa: "もしかして: %s",
+		langEn: "Did you mean: %s",
+	},
+	"iam.failed": {
+		langJa: "調整さんでの名前の登録に失敗しました",
+		langEn: "Failed to register your chouseisan name",
+	},
+	"iam.done": {
+		langJa: "調整さんでの名前を「%s」で登録しました。出欠が未入力のときは、リマインドでメンションします",
+		langEn: "Your chouseisan name has been registered as \"%s\". You will be mentioned on reminders when you have not answered",
+	},
+	"whois.failed": {
+		langJa: "登録したメンバーの取得に失敗しました",
+		langEn: "Failed to get the registered members",
+	},
+	"whois.none": {
+		langJa: "調整さんの名前を登録したメンバーはいません（「/iam 調整さんでの名前」で登録できます）",
+		langEn: "No members have registered their chouseisan names (register with \"/iam your chouseisan name\")",
+	},
+	"whois.title": {
+		langJa: "調整さんの名前を登録したメンバー",
+		langEn: "Members who registered their chouseisan names",
+	},
+	"whois.unknown": {
+		langJa: "(不明)",
+		langEn: "(unknown)",
+	},
+	"whois.unlinked": {
+		langJa: "未登録: %s",
+		langEn: "Not registered: %s",
+	},
+	"subscribe_me.not_linked": {
+		langJa: "個別通知を受け取るには、先に「/iam 調整さんでの名前」で名前を登録してください",
+		langEn: "To receive personal reminders, first register your name with \"/iam your chouseisan name\"",
+	},
+	"subscribe_me.failed": {
+		langJa: "個別通知の設定に失敗しました",
+		langEn: "Failed to set personal reminders",
+	},
+	"subscribe_me.on": {
+		langJa: "回答期限が近づいたら、出欠が未入力/△の日程を1:1トークでお知らせします（BOTを友だち追加してください）",
+		langEn: "Dates you have not answered or answered △ will be sent to you in a 1:1 chat near the deadline (add the bot as a friend)",
+	},
+	"subscribe_me.no_deadline": {
+		langJa: "回答期限が設定されていないため、まだお知らせは届きません。「/set deadline 12/20」のように回答期限を設定してください",
+		langEn: "No answer deadline is set, so nothing will be sent yet. Set one like \"/set deadline 12/20\"",
+	},
+	"subscribe_me.off": {
+		langJa: "個別通知を停止しました",
+		langEn: "Personal reminders have been stopped",
+	},
+
+	// `uidtest`
+	"uidtest.sender": {
+		langJa: "今のメッセージ送信者は、%sさんです",
+		langEn: "The sender of this message is %s",
+	},
+	"uidtest.profile_failed": {
+		langJa: "userProfile取得失敗(%s)",
+		langEn: "Failed to get userProfile (%s)",
+	},
+	"uidtest.uid_failed": {
+		langJa: "userId取得失敗(%s)",
+		langEn: "Failed to get userId (%s)",
+	},
+
+	// usage.html
+	"usage.title": {
+		langJa: "調整さんリマインダBOT（グループ対応）",
+		langEn: "Chouseisan Reminder BOT (for groups)",
+	},
+	"usage.heading": {
+		langJa: "調整さんリマインダBOTの使いかた",
+		langEn: "How to use the Chouseisan Reminder BOT",
+	},
+	"usage.invite.title": {
+		langJa: "1. BOTをグループ/トークルームに招待する",
+		langEn: "1. Invite the BOT to your group/room",
+	},
+	"usage.invite.1": {
+		langJa: "スマートフォンでLINEアプリを起動し、[友だち追加]->[QRコード]で、QRコードリーダー（カメラ）を起動します",
+		langEn: "Open the LINE app on your smartphone and start the QR code reader (camera) from [Add friends]->[QR code]",
+	},
+	"usage.invite.2": {
+		langJa: `<a href="/img/linebot_qr.png"/>LINE BOTのQRコード</a>を開き、表示されるQRコードをQRコードリーダーで取り込みます`,
+		langEn: `Open the <a href="/img/linebot_qr.png"/>QR code of the LINE BOT</a> and scan it with the QR code reader`,
+	},
+	"usage.invite.3": {
+		langJa: "友だちを追加する画面になりますので、［追加］ボタンをタップ。これで、友だち->公式アカウントの下にBOTが増えているはずです（見つからなければ管理者に連絡してください）",
+		langEn: "Tap [Add] on the screen that appears. The BOT should now be listed under Friends->Official accounts (contact the administrator if you cannot find it)",
+	},
+	"usage.invite.4": {
+		langJa: "リマインダを使いたいグループ/トークルームでメニューを開き、［招待］->先に友だち登録したBotを招待します",
+		langEn: "Open the menu of the group/room where you want reminders, and invite the BOT you added from [Invite]",
+	},
+	"usage.invite.5": {
+		langJa: "グループに招待できたら、BOTはブロック（友だち解除）して構いません",
+		langEn: "Once the BOT is in the group, you may block (unfriend) it",
+	},
+	"usage.event.title": {
+		langJa: "2. リマインドする調整さんイベントを設定する",
+		langEn: "2. Set the chouseisan event to remind",
+	},
+	"usage.event.1": {
+		langJa: "グループトークで、<code>/set chouseisan URL</code>と入力します。<code>URL</code>の部分には、リマインドしてほしい調整さんイベントのURLを指定してください。招待した直後であれば、URLだけを送っても設定できます。",
+		langEn: "In the group chat, type <code>/set chouseisan URL</code>, where <code>URL</code> is the URL of the chouseisan event to remind. Right after inviting the BOT, you can also just send the URL.",
+	},
+	"usage.event.2": {
+		langJa: "BOTから「リマインドする調整さんイベントを「イベント名」に設定しました」というメッセージが返ってきたら成功です。これで、開催3日前および当日の朝8:00に、BOTから参加人数などが通知されるようになります。",
+		langEn: "If the BOT replies \"The chouseisan event to remind has been set to \"EVENT NAME\"\", you are done. The BOT will post the attendance at 8:00 three days before and on the day of each event.",
+	},
+	"usage.commands.title": {
+		langJa: "3. その他のコマンド",
+		langEn: "3. Other commands",
+	},
+	"usage.commands.set_name": {
+		langJa: "<code>/set name 表示名</code> グループの表示名を設定できます。グループ名や、1:1で友だち登録した場合のユーザ名は自動で設定されます（<code>/set name auto</code>で自動に戻せます）",
+		langEn: "<code>/set name NAME</code> Sets the display name of the group. Group names and user names of 1:1 chats are set automatically (type <code>/set name auto</code> to go back to automatic)",
+	},
+	"usage.commands.set_quiet": {
+		langJa: "<code>/set quiet 22-7</code> リマインドを送信しない時間帯を設定できます。この時間帯のリマインドは終了時刻まで延期されます。解除するには<code>/set quiet off</code>と入力してください",
+		langEn: "<code>/set quiet 22-7</code> Sets quiet hours. Reminders during quiet hours are postponed until they end. Type <code>/set quiet off</code> to clear",
+	},
+	"usage.commands.set_holiday": {
+		langJa: "<code>/set holiday skip</code> 土日祝日にリマインドしないように設定できます。<code>shift</code>を指定すると直前の平日に前倒しして、<code>send</code>を指定すると土日祝日もリマインドします（デフォルト）",
+		langEn: "<code>/set holiday skip</code> Skips reminders on weekends and (Japanese) holidays. <code>shift</code> moves them to the previous weekday, and <code>send</code> sends them anyway (default)",
+	},
+	"usage.commands.set_deadline": {
+		langJa: "<code>/set deadline 12/20</code> 出欠の回答期限を設定できます。期限の前日に、まだ出欠を入力していないメンバーをお知らせします（<code>/set deadline 12/20 2</code>のように、何日前に知らせるかも指定できます）。解除するには<code>/set deadline off</code>と入力してください",
+		langEn: "<code>/set deadline 12/20</code> Sets the answer deadline. Members who have not answered are listed the day before the deadline (specify the days before like <code>/set deadline 12/20 2</code>). Type <code>/set deadline off</code> to clear",
+	},
+	"usage.commands.set_notify": {
+		langJa: "<code>/set notify changes on</code> リマインドした日程の出欠が変更されたときに、変更内容（例: 「変更: 電次郎 ○→×」）をお知らせします。停止するには<code>/set notify changes off</code>と入力してください",
+		langEn: "<code>/set notify changes on</code> Notifies changes of answers for reminded dates (e.g. \"変更: 電次郎 ○→×\"). Type <code>/set notify changes off</code> to stop",
+	},
+	"usage.commands.iam": {
+		langJa: "<code>/iam 電次郎</code> 調整さんでの名前を登録できます。登録すると、出欠が未入力のときにメンションでお知らせします",
+		langEn: "<code>/iam NAME</code> Registers your name on chouseisan. Then you are mentioned when you have not answered",
+	},
+	"usage.commands.subscribe_me": {
+		langJa: "<code>/subscribe me</code> 回答期限が近づいたら、自分の出欠が未入力/△の日程を1:1トークでお知らせします（<code>/iam</code>での名前の登録と、BOTの友だち追加が必要です）。停止するには<code>/unsubscribe me</code>と入力してください",
+		langEn: "<code>/subscribe me</code> Sends you the dates you have not answered or answered △ in a 1:1 chat near the deadline (requires <code>/iam</code> and adding the BOT as a friend). Type <code>/unsubscribe me</code> to stop",
+	},
+	"usage.commands.whois": {
+		langJa: "<code>/whois</code> 調整さんの名前を登録したメンバーと、まだ登録していないメンバーを表示します",
+		langEn: "<code>/whois</code> Shows members who registered their chouseisan names and those who have not",
+	},
+	"usage.commands.set_timezone": {
+		langJa: "<code>/set timezone Europe/Berlin</code> 開催日やリマインド時刻を解釈するタイムゾーンを設定できます。デフォルトは日本時間（Asia/Tokyo）です",
+		langEn: "<code>/set timezone Europe/Berlin</code> Sets the time zone for event dates and the reminder time. The default is Japan time (Asia/Tokyo)",
+	},
+	"usage.commands.set_remindtime": {
+		langJa: "<code>/set remindtime 19</code> リマインドする時刻を設定できます。時刻を省略すると、選択肢のボタンを表示します",
+		langEn: "<code>/set remindtime 19</code> Sets the reminder time. Without an hour, choices are shown as buttons",
+	},
+	"usage.commands.set_template": {
+		langJa: "<code>/set template {{.Date}} ○{{.Present}}名 ×{{.Absent}}名</code> リマインドのメッセージを自由に書けます。使える項目は、<code>{{.EventTitle}}</code>（イベント名）、<code>{{.Date}}</code>（日程）、<code>{{.Present}}</code>・<code>{{.Maybe}}</code>・<code>{{.Absent}}</code>・<code>{{.Unanswered}}</code>（○・△・×・未入力の人数）、<code>{{.Participants}}</code>・<code>{{.Absentees}}</code>・<code>{{.Undecided}}</code>（○・×・△と未入力の名前）、<code>{{.URL}}</code>（調整さんのURL）です。<code>/preview template</code>で確認でき、<code>/set template default</code>で元のカードに戻せます",
+		langEn: "<code>/set template {{.Date}} ○{{.Present}} ×{{.Absent}}</code> Customizes the reminder message. Available fields are <code>{{.EventTitle}}</code> (event name), <code>{{.Date}}</code> (date), <code>{{.Present}}</code>, <code>{{.Maybe}}</code>, <code>{{.Absent}}</code>, <code>{{.Unanswered}}</code> (number of ○, △, × and no answer), <code>{{.Participants}}</code>, <code>{{.Absentees}}</code>, <code>{{.Undecided}}</code> (names of ○, × and △/no answer) and <code>{{.URL}}</code> (chouseisan URL). Check it with <code>/preview template</code>, and reset to the card with <code>/set template default</code>",
+	},
+	"usage.commands.set_lang": {
+		langJa: "<code>/set lang en</code> BOTの返信を英語にします。日本語に戻すには<code>/set lang ja</code>と入力してください",
+		langEn: "<code>/set lang en</code> Replies in English. Type <code>/set lang ja</code> for Japanese",
+	},
+	"usage.commands.show_settings": {
+		langJa: "<code>/show settings</code> 現在の設定を表示します",
+		langEn: "<code>/show settings</code> Shows the current settings",
+	},
+	"usage.commands.remind_now": {
+		langJa: "<code>/remind now</code> 当日から3日後までの日程の出欠状況を、すぐに表示します",
+		langEn: "<code>/remind now</code> Shows the attendance for the next 3 days right away",
+	},
+	"usage.commands.show_schedule": {
+		langJa: "<code>/show schedule</code> 今後の日程ごとの出欠の人数を、グラフの画像と一緒に表示します",
+		langEn: "<code>/show schedule</code> Shows the number of answers for each upcoming date with a chart",
+	},
+	"usage.commands.help": {
+		langJa: "<code>/help</code> よく使う操作（今すぐ集計、設定を確認、リマインド時刻を変更）をボタンで表示します",
+		langEn: "<code>/help</code> Shows frequent actions (attendance now, show settings, change remind time) as buttons",
+	},
+	"usage.commands.version": {
+		langJa: "<code>/version</code> BOTのバージョン番号を表示します",
+		langEn: "<code>/version</code> Shows the version of the BOT",
+	},
+	"usage.others.title": {
+		langJa: "4. その他の操作、問題",
+		langEn: "4. Other operations and problems",
+	},
+	"usage.others.1": {
+		langJa: "リマインダを解除するには、BOTをグループ/トークルームから「削除」してください",
+		langEn: "To stop the reminder, remove the BOT from the group/room",
+	},
+	"usage.others.2": {
+		langJa: "何か問題を発見したら、操作した時刻を添えて管理者まで連絡してください",
+		langEn: "If you find a problem, contact the administrator with the time of the operation",
+	},
+}
+
+/**
+ * 対応している言語であればtrueを返す
+ */
+func isSupportedLang(lang string) bool {
+	for _, v := range supportedLangs {
+		if v == lang {
+			return true
+		}
+	}
+	return false
+}
+
+/**
+ * メッセージカタログから、指定した言語の文字列を返す。訳がなければ日本語、キーがなければキーをそのまま返す
+ */
+func msg(lang string, key string, args ...interface{}) string {
+	entry, exist := catalog[key]
+	if !exist {
+		return key
+	}
+	format, exist := entry[lang]
+	if !exist {
+		format = entry[defaultLang]
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+/**
+ * usage.htmlで使う、言語を固定したメッセージカタログの関数を返す（カタログのHTMLはエスケープしない）
+ */
+func usageFuncs(lang string) template.FuncMap {
+	return template.FuncMap{
+		"msg": func(key string) template.HTML {
+			return template.HTML(msg(lang, key))
+		},
+	}
+}
+
+/**
+ * Accept-Languageヘッダから、対応している言語を返す。対応していなければデフォルトの言語
+ */
+func acceptLang(header string) string {
+	for _, v := range strings.Split(header, ",") {
+		tag := strings.ToLower(strings.TrimSpace(strings.SplitN(v, ";", 2)[0]))
+		for _, lang := range supportedLangs {
+			if tag == lang || strings.HasPrefix(tag, lang+"-") {
+				return lang
+			}
+		}
+	}
+	return defaultLang
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"regexp"
+	"testing"
+)
+
+/**
+ * すべてのキーに対応している言語の訳があり、埋め込む引数の数が一致すること
+ */
+func TestCatalog(t *testing.T) {
+	verb := regexp.MustCompile(`%(?:\[[0-9]+\])?[a-z]`)
+	for key, entry := range catalog {
+		for _, lang := range supportedLangs {
+			if _, exist := entry[lang]; !exist {
+				t.Errorf("Missing translation. key:%v, lang:%v", key, lang)
+			}
+		}
+		if ja, en := verb.FindAllString(entry[langJa], -1), verb.FindAllString(entry[langEn], -1); len(ja) != len(en) {
+			t.Errorf("Unmatch number of arguments. key:%v, ja:%v, en:%v", key, ja, en)
+		}
+	}
+}
+
+/**
+ * メッセージカタログから文字列を取り出す
+ */
+func TestMsg(t *testing.T) {
+	type testParameter struct {
+		lang     string
+		key      string
+		args     []interface{}
+		expected string
+	}
+	testCases := []testParameter{{
+		lang:     langJa,
+		key:      "set_remindtime.done",
+		args:     []interface{}{19},
+		expected: "リマインド時刻を19:00に設定しました",
+	}, {
+		lang:     langEn,
+		key:      "set_remindtime.done",
+		args:     []interface{}{19},
+		expected: "The reminder time has been set to 19:00",
+	}, {
+		lang:     langEn,
+		key:      "deadline.before", // 引数の順番を入れ替える
+		args:     []interface{}{12, 20, 1},
+		expected: "1 day(s) left until the answer deadline 12/20",
+	}, {
+		lang:     "fr", // 対応していない言語は日本語
+		key:      "set_name.done",
+		expected: "グループ（もしくはトークルーム）の名前を設定しました",
+	}, {
+		lang:     langJa,
+		key:      "unknown.key", // 未定義のキーはそのまま
+		expected: "unknown.key",
+	}}
+
+	for _, current := range testCases {
+		if actual := msg(current.lang, current.key, current.args...); actual != current.expected {
+			t.Errorf("Illegal return value. key:%v, returnd:%v", current.key, actual)
+		}
+	}
+}
+
+/**
+ * Accept-Languageヘッダから言語を選ぶ
+ */
+func TestAcceptLang(t *testing.T) {
+	testCases := map[string]string{
+		"":                        langJa,
+		"en-US,en;q=0.9,ja;q=0.8": langEn,
+		"ja-JP,ja;q=0.9,en;q=0.8": langJa,
+		"fr-FR, en;q=0.5":         langEn, // 対応している言語まで読み飛ばす
+		"fr-FR,de;q=0.8":          langJa,
+	}
+	for header, expected := range testCases {
+		if actual := acceptLang(header); actual != expected {
+			t.Errorf("Illegal return value. header:%v, returnd:%v", header, actual)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"sort"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/line/line-bot-sdk-go/linebot"
+
+	"google.golang.org/appengine/datastore"
+	"google.golang.org/appengine/log"
+)
+
+// 出欠スナップショットの、日程・メンバーごとのエントリ（検索しないので、インデックスは作らない）
+type snapshotAnswer struct {
+	Date       string `datastore:",noindex"` // 開催日（scheduleMapのキー）
+	DateString string `datastore:",noindex"` // 日程欄（文字列）
+	Name       string `datastore:",noindex"` // メンバーの名前
+	Answer     string `datastore:",noindex"` // 出欠（"○"、"△"、"×"、未入力は空文字）
+}
+
+// 前回クロール時の出欠を保存するエンティティ（keyはMID）
+type answerSnapshot struct {
+	MID            string
+	ChouseisanHash string           // スナップショットを取得した調整さんのハッシュ
+	Answers        []snapshotAnswer `datastore:",noindex"` // 日程×メンバーの数だけあるので、インデックスは作らない
+	UpdateTime     time.Time
+}
+
+// 出欠の変更
+type answerChange struct {
+	Date       string // 開催日（scheduleMapのキー）
+	DateString string // 日程欄（文字列）
+	Name       string // メンバーの名前
+	Before     string // 変更前の出欠
+	After      string // 変更後の出欠
+}
+
+/**
+ * 調整さんスケジュールから、出欠スナップショットのエントリを生成する（開催日、csvの列順）
+ */
+func (m scheduleMap) snapshotAnswers() []snapshotAnswer {
+	dates := []time.Time{}
+	for _, s := range m {
+		dates = append(dates, s.Date)
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+	answers := []snapshotAnswer{}
+	for _, date := range dates {
+		s := m[date.String()]
+		for _, name := range s.Names {
+			answers = append(answers, snapshotAnswer{
+				Date:       date.String(),
+				DateString: s.DateString,
+				Name:       name,
+				Answer:     s.Answers[name],
+			})
+		}
+	}
+	return answers
+}
+
+/**
+ * 前回と今回のスナップショットを比較して、出欠の変更を返す
+ *
+ * 前回のスナップショットに存在しない日程（追加された日程）は対象外。追加されたメンバーは未入力からの変更として扱う
+ */
+func diffAnswers(before []snapshotAnswer, after []snapshotAnswer) []answerChange {
+	dates := map[string]bool{}
+	previous := map[string]string{}
+	for _, v := range before {
+		dates[v.Date] = true
+		previous[v.Date+"\t"+v.Name] = v.Answer
+	}
+
+	changes := []answerChange{}
+	for _, v := range after {
+		if !dates[v.Date] {
+			continue
+		}
+		if old := previous[v.Date+"\t"+v.Name]; old != v.Answer {
+			changes = append(changes, answerChange{
+				Date:       v.Date,
+				DateString: v.DateString,
+				Name:       v.Name,
+				Before:     old,
+				After:      v.Answer,
+			})
+		}
+	}
+	return changes
+}
+
+/**
+ * 出欠の表示用文字列を返す（未入力は"未入力"）
+ */
+func answerLabel(lang string, answer string) string {
+	if answer == "" {
+		return msg(lang, "answer.unanswered")
+	}
+	return answer
+}
+
+/**
+ * 出欠の変更を通知するメッセージを組み立てて返す
+ */
+func constructChangesMessage(lang string, changes []answerChange, hash string) string {
+	message := msg(lang, "changes.title")
+	dateString := ""
+	for _, v := range changes {
+		if v.DateString != dateString {
+			dateString = v.DateString
+			message += "\n\n" + dateString
+		}
+		message += "\n" + msg(lang, "changes.change", v.Name, answerLabel(lang, v.Before), answerLabel(lang, v.After))
+	}
+	return message + "\n\n" + msg(lang, "changes.detail", "https://chouseisan.com/s?h="+hash)
+}
+
+/**
+ * 出欠スナップショットを更新して、前回からの変更を返す
+ *
+ * 前回のスナップショットがない、もしくは調整さんイベントが変わった場合は、変更なしとして扱う
+ */
+func updateAnswerSnapshot(c context.Context, current *subscriber, m scheduleMap) ([]answerChange, error) {
+	var previous answerSnapshot
+
+	key := datastore.NewKey(c, "AnswerSnapshot", current.MID, 0, nil)
+	if err := datastore.Get(c, key, &previous); err != nil && err != datastore.ErrNoSuchEntity {
+		log.Errorf(c, "Error occurred at get AnswerSnapshot entity. mid:%v err:%v", current.MID, err)
+		return nil, err
+	}
+
+	entity := answerSnapshot{
+		MID:            current.MID,
+		ChouseisanHash: current.ChouseisanHash,
+		Answers:        m.snapshotAnswers(),
+		UpdateTime:     time.Now(),
+	}
+	if _, err := datastore.Put(c, key, &entity); err != nil {
+		log.Errorf(c, "Error occurred at put AnswerSnapshot entity. mid:%v err:%v", current.MID, err)
+		return nil, err
+	}
+
+	if previous.ChouseisanHash != current.ChouseisanHash {
+		return []answerChange{}, nil
+	}
+	return diffAnswers(previous.Answers, entity.Answers), nil
+}
+
+/**
+ * 出欠スナップショットを更新して、リマインド済み（当日から3日後まで）の日程に変更があればPush Messageを送信する
+ *
+ * pushにfalseを指定すると、スナップショットの更新のみ行なう
+ */
+func notifyChanges(c context.Context, bot *linebot.Client, current *subscriber, m scheduleMap, today time.Time, push bool) {
+	changes, err := updateAnswerSnapshot(c, current, m)
+	if err != nil || !push {
+		return
+	}
+
+	tz := current.location()
+	today = today.In(tz)
+	from := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, tz)
+	to := from.AddDate(0, 0, 3)
+
+	reminded := []answerChange{}
+	for _, v := range changes {
+		if s, exist := m[v.Date]; exist && !s.Date.Before(from) && !s.Date.After(to) {
+			reminded = append(reminded, v)
+		}
+	}
+	if len(reminded) == 0 {
+		return
+	}
+
+	log.Infof(c, "Notify changes! subscriber:%v changes:%v", current.DisplayName, len(reminded))
+	message := constructChangesMessage(current.lang(), reminded, current.ChouseisanHash)
+	if err := sendPush(bot, current.MID, newTextMessages(message)); err != nil {
+		log.Errorf(c, "Error occurred at notify changes. subscriber:%v, err: %v", current.DisplayName, err)
+	}
+}
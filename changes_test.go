@@ -0,0 +1,92 @@
+package main
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"google.golang.org/appengine/aetest"
+)
+
+/**
+ * 変更前後の調整さんcsvをパースして、出欠スナップショットのエントリを返す
+ */
+func readSnapshotAnswers(t *testing.T, filename string) []snapshotAnswer {
+	c, done, err := aetest.NewContext()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer done()
+
+	//テストデータはファイルから読む
+	testdata, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer testdata.Close()
+
+	tz, _ := time.LoadLocation("Asia/Tokyo")
+	today := time.Date(2016, time.December, 1, 0, 0, 0, 0, tz)
+	return parseCsv(c, testdata, today).snapshotAnswers()
+}
+
+/**
+ * 出欠スナップショットの比較
+ */
+func TestDiffAnswers(t *testing.T) {
+	before := readSnapshotAnswers(t, "testdata/chouseisan/changes_before.csv")
+	after := readSnapshotAnswers(t, "testdata/chouseisan/changes_after.csv")
+
+	// 追加された日程（12/31）は対象外、追加されたメンバー（電五郎）は未入力からの変更
+	expected := []answerChange{
+		{DateString: "12/17(土) 19:00〜", Name: "電次郎", Before: "○", After: "×"},
+		{DateString: "12/17(土) 19:00〜", Name: "電三太郎", Before: "", After: "○"},
+		{DateString: "12/17(土) 19:00〜", Name: "電五郎", Before: "", After: "○"},
+		{DateString: "12/24(土) 19:00〜", Name: "電四郎", Before: "○", After: ""},
+	}
+	actual := diffAnswers(before, after)
+	if len(actual) != len(expected) {
+		t.Fatalf("Unmatch changes count: %v", actual)
+	}
+	for i, v := range expected {
+		if actual[i].DateString != v.DateString || actual[i].Name != v.Name || actual[i].Before != v.Before || actual[i].After != v.After {
+			t.Errorf("Unmatch change[%v]. expected:%v, actual:%v", i, v, actual[i])
+		}
+	}
+
+	// 同じスナップショット同士では変更なし
+	if actual := diffAnswers(after, after); len(actual) != 0 {
+		t.Errorf("Changes found in same snapshot: %v", actual)
+	}
+}
+
+/**
+ * 出欠変更の通知メッセージ組み立て
+ */
+func TestConstructChangesMessage(t *testing.T) {
+	changes := []answerChange{
+		{DateString: "12/17(土) 19:00〜", Name: "電次郎", Before: "○", After: "×"},
+		{DateString: "12/17(土) 19:00〜", Name: "電三太郎", Before: "", After: "○"},
+		{DateString: "12/24(土) 19:00〜", Name: "電四郎", Before: "○", After: ""},
+	}
+	expected := "出欠が変更されました\n\n" +
+		"12/17(土) 19:00〜\n変更: 電次郎 ○→×\n変更: 電三太郎 未入力→○\n\n" +
+		"12/24(土) 19:00〜\n変更: 電四郎 ○→未入力" +
+		"\n\n詳細は「調整さん」へ\n" +
+		"https://chouseisan.com/s?h=3f7ffd73ba174332ae05bd363eba8e71"
+	actual := constructChangesMessage(langJa, changes, "3f7ffd73ba174332ae05bd363eba8e71")
+	if actual != expected {
+		t.Errorf("Unmatch message\nexpect:\n%v\nactual:\n%v", expected, actual)
+	}
+
+	// 英語のグループ
+	expected = "Answers have been changed\n\n" +
+		"12/17(土) 19:00〜\nChanged: 電次郎 ○→×\nChanged: 電三太郎 no answer→○\n\n" +
+		"12/24(土) 19:00〜\nChanged: 電四郎 ○→no answer" +
+		"\n\nSee chouseisan for details\n" +
+		"https://chouseisan.com/s?h=3f7ffd73ba174332ae05bd363eba8e71"
+	actual = constructChangesMessage(langEn, changes, "3f7ffd73ba174332ae05bd363eba8e71")
+	if actual != expected {
+		t.Errorf("Unmatch message\nexpect:\n%v\nactual:\n%v", expected, actual)
+	}
+}
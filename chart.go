@@ -0,0 +1,172 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/net/context"
+
+	"google.golang.org/appengine"
+	"google.golang.org/appengine/log"
+	"google.golang.org/appengine/urlfetch"
+)
+
+// 出欠グラフの画像のサイズなど
+const (
+	chartWidth        = 1040
+	chartHeight       = 585
+	chartMargin       = 40 // 上下左右の余白（下は日付のラベルの分を加える）
+	chartLabelHeight  = 20
+	chartMaxDates     = 31                 // グラフに描画する日程の最大数
+	chartURLExpiresIn = 7 * time.Hour * 24 // 画像URLの有効期限（LINEのトーク履歴から再表示されるため長めにする）
+)
+
+/**
+ * "#RRGGBB"形式の色を返す
+ */
+func parseHexColor(hex string) color.RGBA {
+	v, _ := strconv.ParseUint(hex[1:], 16, 32)
+	return color.RGBA{uint8(v >> 16), uint8(v >> 8), uint8(v), 0xff}
+}
+
+/**
+ * 文字列を、中央揃えで描画する（ASCII文字のみ）
+ */
+func drawCenteredString(img draw.Image, text string, centerX int, baselineY int, c color.Color) {
+	d := &font.Drawer{Dst: img, Src: image.NewUniform(c), Face: basicfont.Face7x13}
+	d.Dot = fixed.P(centerX-d.MeasureString(text).Round()/2, baselineY)
+	d.DrawString(text)
+}
+
+/**
+ * 日程ごとの出欠（○/△/×/未入力）を積み上げ棒グラフにした画像を返す
+ *
+ * 色はリマインドのカードと揃える。棒の中に人数、棒の下に日付を描画する
+ */
+func renderAttendanceChart(schedules []schedule) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, chartWidth, chartHeight))
+	draw.Draw(img, img.Bounds(), image.White, image.ZP, draw.Src)
+	if len(schedules) > chartMaxDates {
+		schedules = schedules[:chartMaxDates]
+	}
+
+	max := 1
+	for _, s := range schedules {
+		if len(s.Names) > max {
+			max = len(s.Names)
+		}
+	}
+
+	bottom := chartHeight - chartMargin - chartLabelHeight
+	plotHeight := bottom - chartMargin
+	slot := (chartWidth - chartMargin*2) / chartMaxDates
+	if len(schedules) > 0 {
+		slot = (chartWidth - chartMargin*2) / len(schedules)
+	}
+	barWidth := slot * 2 / 3
+
+	for i, s := range schedules {
+		x := chartMargin + slot*i + (slot-barWidth)/2
+		y := bottom
+		for _, row := range answerRows {
+			count := len(s.namesByAnswer(row.Answer))
+			if count == 0 {
+				continue
+			}
+			height := plotHeight * count / max
+			draw.Draw(img, image.Rect(x, y-height, x+barWidth, y), image.NewUniform(parseHexColor(row.Color)), image.ZP, draw.Src)
+			if height >= basicfont.Face7x13.Height {
+				drawCenteredString(img, strconv.Itoa(count), x+barWidth/2, y-height/2+basicfont.Face7x13.Ascent/2, color.White)
+			}
+			y -= height
+		}
+		drawCenteredString(img, s.Date.Format("1/2"), x+barWidth/2, bottom+chartLabelHeight, color.Black)
+	}
+
+	// 横軸
+	draw.Draw(img, image.Rect(chartMargin, bottom, chartWidth-chartMargin, bottom+1), image.Black, image.ZP, draw.Src)
+	return img
+}
+
+/**
+ * 出欠グラフの画像URLの署名を返す
+ */
+func chartSignature(mid string, expires string) string {
+	mac := hmac.New(sha256.New, []byte(os.Getenv("LINE_CHANNEL_SECRET")))
+	mac.Write([]byte(mid + "\n" + expires))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+/**
+ * 出欠グラフの画像の、署名付きURLを返す
+ */
+func signChartURL(host string, mid string, expires time.Time) string {
+	exp := strconv.FormatInt(expires.Unix(), 10)
+	return "https://" + host + "/chart?" + url.Values{
+		"mid": {mid},
+		"exp": {exp},
+		"sig": {chartSignature(mid, exp)},
+	}.Encode()
+}
+
+/**
+ * 出欠グラフの画像URLの署名と有効期限を検証する
+ */
+func verifyChartSignature(mid string, exp string, sig string, now time.Time) bool {
+	expires, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil || now.Unix() > expires {
+		return false
+	}
+	return hmac.Equal([]byte(sig), []byte(chartSignature(mid, exp)))
+}
+
+/**
+ * 出欠グラフの画像（PNG）を返す
+ *
+ * 引数にContextとhttp.Clientを取るインナーメソッド
+ */
+func chartWithContext(c context.Context, client *http.Client, w http.ResponseWriter, r *http.Request) {
+	mid := r.FormValue("mid")
+	if !verifyChartSignature(mid, r.FormValue("exp"), r.FormValue("sig"), time.Now()) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	entity, err := readSubscriber(c, mid)
+	if err != nil || entity.ChouseisanHash == "" {
+		http.NotFound(w, r)
+		return
+	}
+	today := time.Now().In(entity.location())
+	m := fetchScheduleMap(c, client, entity, today)
+	if m == nil {
+		http.Error(w, "Failed to fetch chouseisan", http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	if err = png.Encode(w, renderAttendanceChart(m.futureSchedules(today))); err != nil {
+		log.Errorf(c, "Error occurred at encode chart. mid:%v, err: %v", mid, err)
+	}
+}
+
+/**
+ * 出欠グラフの画像（PNG）を返す
+ */
+func chart(w http.ResponseWriter, r *http.Request) {
+	c := appengine.NewContext(r)
+	chartWithContext(c, urlfetch.Client(c), w, r)
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"image/color"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+/**
+ * 出欠グラフの画像URLの署名と検証
+ */
+func TestSignChartURL(t *testing.T) {
+	now := time.Date(2016, time.December, 20, 8, 0, 0, 0, time.UTC)
+	chartURL := signChartURL("example.appspot.com", "C00000000000000000000000000000000", now.Add(time.Hour))
+	if !strings.HasPrefix(chartURL, "https://example.appspot.com/chart?") {
+		t.Fatalf("Unmatch chart url: %v", chartURL)
+	}
+	u, err := url.Parse(chartURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+
+	if !verifyChartSignature(q.Get("mid"), q.Get("exp"), q.Get("sig"), now) {
+		t.Errorf("Valid signature was rejected: %v", chartURL)
+	}
+	if verifyChartSignature(q.Get("mid"), q.Get("exp"), q.Get("sig"), now.Add(2*time.Hour)) {
+		t.Errorf("Expired signature was accepted: %v", chartURL)
+	}
+	if verifyChartSignature("C00000000000000000000000000000001", q.Get("exp"), q.Get("sig"), now) {
+		t.Errorf("Signature for other mid was accepted: %v", chartURL)
+	}
+}
+
+/**
+ * 出欠グラフの描画（棒の下から○/△/×/未入力の順に積み上がる）
+ */
+func TestRenderAttendanceChart(t *testing.T) {
+	tz, _ := time.LoadLocation("Asia/Tokyo")
+	schedules := []schedule{{
+		Date:    time.Date(2016, time.December, 24, 0, 0, 0, 0, tz),
+		Names:   []string{"電一", "電次郎"},
+		Answers: map[string]string{"電一": "○", "電次郎": "×"},
+	}}
+
+	img := renderAttendanceChart(schedules)
+	if img.Bounds().Dx() != chartWidth || img.Bounds().Dy() != chartHeight {
+		t.Fatalf("Unmatch chart size: %v", img.Bounds())
+	}
+
+	// 1本の棒の中央、下端（○）と上端（×）の色
+	x := chartWidth / 2
+	bottom := chartHeight - chartMargin - chartLabelHeight
+	if actual := img.At(x-10, bottom-5); actual != color.Color(parseHexColor("#1DB446")) {
+		t.Errorf("Unmatch color of present: %v", actual)
+	}
+	if actual := img.At(x-10, chartMargin+5); actual != color.Color(parseHexColor("#E5484D")) {
+		t.Errorf("Unmatch color of absent: %v", actual)
+	}
+}
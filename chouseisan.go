@@ -2,8 +2,11 @@ package main
 
 import (
 	"encoding/csv"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"regexp"
 	"strconv"
 	"time"
@@ -17,42 +20,51 @@ import (
 	"google.golang.org/appengine"
 	"google.golang.org/appengine/datastore"
 	"google.golang.org/appengine/log"
+	"google.golang.org/appengine/taskqueue"
 	"google.golang.org/appengine/urlfetch"
 )
 
 // 調整さんの開催日ごとの集計エントリ
 type schedule struct {
-	Date             time.Time // 開催日（時間は00:00:00JST）
-	DateString       string    // 日程欄（文字列）
-	Present          int       // ◯
-	Absent           int       // ×
-	Unknown          int       // △および未入力
-	ParticipantsName string    // 参加者の名前を列挙したもの
-	UnknownName      string    // △および未入力の名前を列挙したもの
+	EventTitle       string            // 調整さんのイベント名
+	Date             time.Time         // 開催日（時間は購読者のタイムゾーンで00:00:00）
+	DateString       string            // 日程欄（文字列）
+	Present          int               // ◯
+	Absent           int               // ×
+	Unknown          int               // △および未入力
+	ParticipantsName string            // 参加者の名前を列挙したもの
+	UnknownName      string            // △および未入力の名前を列挙したもの
+	Names            []string          // 出欠を入力するメンバーの名前（csvの列順）
+	Answers          map[string]string // メンバーごとの出欠（"○"、"△"、"×"、未入力は空文字）
 }
 
 // 送信メッセージ用のサマリを組み立てて返す
-func (s *schedule) constructSummaryBody() string {
-	return s.DateString + "の出欠状況をお知らせします\n\n" +
-		"参加: " + strconv.Itoa(s.Present) + "名" + s.ParticipantsName +
-		"\n不参加: " + strconv.Itoa(s.Absent) + "名\n" +
-		"不明/未入力: " + strconv.Itoa(s.Unknown) + "名" + s.UnknownName
+func (s *schedule) constructSummaryBody(lang string) string {
+	return msg(lang, "summary.body", s.DateString, s.Present, s.ParticipantsName, s.Absent, s.Unknown, s.UnknownName)
 }
 
 // 送信メッセージ用のサマリを組み立てて返す
-func (s *schedule) constructSummary(hash string) string {
-	return s.constructSummaryBody() +
-		"\n\n詳細および出欠変更は「調整さん」へ\nhttps://chouseisan.com/s?h=" + hash
+func (s *schedule) constructSummary(lang string, hash string) string {
+	return s.constructSummaryBody(lang) + msg(lang, "summary.footer", "https://chouseisan.com/s?h="+hash)
 }
 
+// 調整さんイベントの取得に失敗した理由
+var (
+	errChouseisanNotFound   = errors.New("chouseisan event not found")
+	errChouseisanUnparsable = errors.New("chouseisan csv is unparsable")
+)
+
 // 調整さんスケジュールのMap型
 type scheduleMap map[string]schedule
 
 /**
  * 調整さんcsvをパースして、参加人数などを集計する
+ *
+ * 開催日はtodayと同じタイムゾーンの日付として解釈する
  */
 func parseCsv(c context.Context, csvBody io.ReadCloser, today time.Time) (m scheduleMap) {
 	var (
+		title    string
 		names    []string
 		rowCount = 0
 	)
@@ -70,8 +82,14 @@ func parseCsv(c context.Context, csvBody io.ReadCloser, today time.Time) (m sche
 			return nil
 		}
 
-		if rowCount < 2 {
-			//イベント名、詳細説明文はスキップ
+		if rowCount == 0 {
+			//イベント名
+			if len(row) > 0 {
+				title = row[0]
+			}
+
+		} else if rowCount == 1 {
+			//詳細説明文はスキップ
 
 		} else if rowCount == 2 {
 			//名前行
@@ -83,11 +101,11 @@ func parseCsv(c context.Context, csvBody io.ReadCloser, today time.Time) (m sche
 
 		} else {
 			//データ行（最終のコメント行も含む）
-			s := schedule{}
+			s := schedule{EventTitle: title, Answers: map[string]string{}}
 			for i, v := range row {
 				if i == 0 {
 					//日付カラムはパースしてキーにする
-					tz, _ := time.LoadLocation("Asia/Tokyo")
+					tz := today.Location()
 					year := today.Year()
 					r := regexp.MustCompile(`^(\d{1,2})/(\d{1,2}).*$`)
 					md := r.FindAllStringSubmatch(v, -1)
@@ -119,29 +137,33 @@ func parseCsv(c context.Context, csvBody io.ReadCloser, today time.Time) (m sche
 
 				} else if len(names[i-1]) > 0 {
 					//出欠カラムの内容を、scheduleに足しこむ
+					s.Names = append(s.Names, names[i-1])
+					s.Answers[names[i-1]] = v
 					if v == "○" {
 						s.Present++
-						if len(s.ParticipantsName) > 0 {
-							s.ParticipantsName += ","
-						}
-						s.ParticipantsName += names[i-1]
 					} else if v == "×" {
 						s.Absent++
 					} else {
 						s.Unknown++
-						if len(s.UnknownName) > 0 {
-							s.UnknownName += ","
-						}
-						s.UnknownName += names[i-1]
 					}
 				}
 			}
 			if len(s.DateString) > 0 {
-				if len(s.ParticipantsName) > 0 {
-					s.ParticipantsName = "(" + s.ParticipantsName + ")"
+				//名前の列挙は、長くなりすぎないよう「他N名」で省略する
+				participants := []string{}
+				unknowns := []string{}
+				for _, name := range s.Names {
+					if s.Answers[name] == "○" {
+						participants = append(participants, name)
+					} else if s.Answers[name] != "×" {
+						unknowns = append(unknowns, name)
+					}
+				}
+				if len(participants) > 0 {
+					s.ParticipantsName = "(" + truncateNames(participants, ",", maxNamesLength) + ")"
 				}
-				if len(s.UnknownName) > 0 {
-					s.UnknownName = "(" + s.UnknownName + ")"
+				if len(unknowns) > 0 {
+					s.UnknownName = "(" + truncateNames(unknowns, ",", maxNamesLength) + ")"
 				}
 				m[s.Date.String()] = s
 			}
@@ -152,48 +174,123 @@ func parseCsv(c context.Context, csvBody io.ReadCloser, today time.Time) (m sche
 }
 
 /**
- * 購読者ごとのイテレーション処理。調整さんをクロールして通知対象があれば集計して返す
+ * 購読者の調整さんイベントをクロールして、csvをパースした結果を返す。取得に失敗した場合はnil
+ *
+ * todayは基準日（購読者のタイムゾーンで解釈する）
  */
-func chouseisanIterator(current *subscriber, c context.Context, client *http.Client, w http.ResponseWriter, r *http.Request) []schedule {
-	result := []schedule{}
+func fetchScheduleMap(c context.Context, client *http.Client, current *subscriber, today time.Time) scheduleMap {
+	m, err := fetchChouseisanCsv(c, client, current.ChouseisanHash, today.In(current.location()))
+	if err != nil {
+		log.Errorf(c, "Get chouseisan's csv failed. hash:%v err: %v", current.ChouseisanHash, err)
+		return nil
+	}
+	return m
+}
 
+/**
+ * 調整さんハッシュのイベントをクロールして、csvをパースした結果を返す
+ *
+ * イベントが存在しなければerrChouseisanNotFound、csvとして読み取れなければerrChouseisanUnparsableを返す
+ */
+func fetchChouseisanCsv(c context.Context, client *http.Client, hash string, today time.Time) (scheduleMap, error) {
 	//調整さんの"出欠表をダウンロード"リンクからcsv形式で取得
-	url := "https://chouseisan.com/schedule/List/createCsv?h=" + current.ChouseisanHash
+	url := "https://chouseisan.com/schedule/List/createCsv?h=" + hash
 	res, err := client.Get(url)
 	if err != nil {
-		log.Errorf(c, "Get chouseisan's csv failed. err: %v", err)
-		return result
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusNotFound {
+		return nil, errChouseisanNotFound
 	} else if res.StatusCode != 200 {
-		log.Errorf(c, "Get chouseisan's csv failed. StatusCode: %v", res.StatusCode)
-		return result
+		return nil, fmt.Errorf("unexpected status code: %v", res.StatusCode)
 	}
 
 	//csvをパース
-	tz, _ := time.LoadLocation("Asia/Tokyo")
-	today := time.Now().In(tz)
 	m := parseCsv(c, res.Body, today)
-
-	//当日の予定をピック
-	targetDate := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, tz)
-	obj, exist := m[targetDate.String()]
-	if exist {
-		result = append(result, obj)
-	} else {
-		log.Debugf(c, "Not found schedule at today.")
+	if m == nil {
+		return nil, errChouseisanUnparsable
 	}
+	return m, nil
+}
 
-	//3日後の予定をピック
-	targetDate = targetDate.AddDate(0, 0, 3)
-	obj, exist = m[targetDate.String()]
-	if exist {
-		result = append(result, obj)
-	} else {
-		log.Debugf(c, "Not found schedule at 3 days after.")
+/**
+ * 購読者ごとのイテレーション処理。クロールした日程から通知対象を集計して返す
+ *
+ * todayは基準日（購読者のタイムゾーンで解釈する）。当日および3日後の予定が通知対象となる
+ * 土日祝日の方針によっては、前倒しされた日の予定も対象となる
+ */
+func chouseisanIterator(current *subscriber, c context.Context, m scheduleMap, today time.Time) []schedule {
+	result := []schedule{}
+	tz := current.location()
+	today = today.In(tz)
+
+	//土日祝日の方針に従って、リマインドすべき日ごとに当日および3日後の予定をピック
+	picked := map[string]bool{}
+	for _, day := range current.reminderDays(today) {
+		baseDate := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, tz)
+		for _, targetDate := range []time.Time{baseDate, baseDate.AddDate(0, 0, 3)} {
+			if picked[targetDate.String()] {
+				continue
+			}
+			obj, exist := m[targetDate.String()]
+			if exist {
+				result = append(result, obj)
+				picked[targetDate.String()] = true
+			} else {
+				log.Debugf(c, "Not found schedule at %v.", targetDate.Format("2006-01-02"))
+			}
+		}
 	}
 
 	return result
 }
 
+/**
+ * クロールした調整さんイベントに、リマインド対象イベントがあればPush Messageを送信する
+ */
+func remindSubscriber(c context.Context, client *http.Client, bot *linebot.Client, current *subscriber, m scheduleMap, today time.Time) {
+	for _, v := range chouseisanIterator(current, c, m, today) {
+		log.Infof(c, "Remind event! subscriber:%v date:%v", current.DisplayName, v.DateString)
+		message, err := v.constructReminderMessageFor(c, current)
+		if err != nil {
+			log.Errorf(c, "Error occurred at construct reminder message. subscriber:%v, date:%v, err: %v", current.DisplayName, v.DateString, err)
+			continue
+		}
+		if err = sendPush(bot, current.MID, []linebot.SendingMessage{message}); err != nil {
+			log.Errorf(c, "Error occurred at crawl chouseisan. subscriber:%v, date:%v, err: %v", current.DisplayName, v.DateString, err)
+			continue
+		}
+
+		// 出欠が未入力のメンバーをメンションする
+		mentionUnanswered(c, client, current, &v)
+	}
+
+	// 回答期限が近ければ、未回答のメンバーに入力を促す
+	remindDeadline(c, bot, current, m, today)
+
+	// 個別通知を受け取るメンバーには、1:1トークでも入力を促す
+	remindPersonal(c, bot, current, m, today)
+}
+
+/**
+ * リマインドを送信しない時間帯の終了時刻に、リマインド処理を延期する
+ *
+ * 基準日は延期前の日付のまま引き継ぐ（日付をまたいでも通知対象の予定が変わらないように）
+ */
+func deferRemind(c context.Context, current *subscriber, now time.Time) {
+	task := taskqueue.NewPOSTTask("/task/remind", url.Values{
+		"mid":  {current.MID},
+		"date": {now.Format("2006-01-02")},
+	})
+	task.ETA = current.quietHoursEnd(now)
+	if _, err := taskqueue.Add(c, task, "default"); err != nil {
+		log.Errorf(c, "Error occurred at defer remind. subscriber:%v, err: %v", current.DisplayName, err)
+		return
+	}
+	log.Infof(c, "Defer remind in quiet hours. subscriber:%v eta:%v", current.DisplayName, task.ETA)
+}
+
 /**
  * 調整さんをクロールして出欠を通知
  *
@@ -205,6 +302,8 @@ func crawlChouseisanWithContext(c context.Context, client *http.Client, w http.R
 		return
 	}
 
+	now := time.Now()
+
 	//hashの入ってるエンティティを抽出してループ
 	ite := datastore.NewQuery("Subscriber").Run(c)
 	for {
@@ -217,33 +316,91 @@ func crawlChouseisanWithContext(c context.Context, client *http.Client, w http.R
 			break
 		}
 
-		if cSubscriber.ChouseisanHash != "" {
-			// ハッシュが設定されていれば、調整さんイベントをクロール
-			log.Infof(c, "Crawl chouseisan! subscriber:%v hash:%v", cSubscriber.DisplayName, cSubscriber.ChouseisanHash)
-			result := chouseisanIterator(&cSubscriber, c, client, w, r)
-
-			// リマインド対象イベントがあれば、Push Messageを送信
-			for _, v := range result {
-				log.Infof(c, "Remind event! subscriber:%v date:%v", cSubscriber.DisplayName, v.DateString)
-				template := linebot.NewButtonsTemplate(
-					"", //サムネイル
-					"", //タイトル
-					v.constructSummaryBody(), //画像もタイトルも指定しない場合：160文字以内
-					linebot.NewURITemplateAction("出欠を登録（変更）する", "https://chouseisan.com/s?h="+cSubscriber.ChouseisanHash),
-				)
-				altText := v.constructSummary(cSubscriber.ChouseisanHash)
-				if _, err = bot.PushMessage(cSubscriber.MID, linebot.NewTemplateMessage(altText, template)).Do(); err != nil {
-					log.Errorf(c, "Error occurred at crawl chouseisan. subscriber:%v, date:%v, err: %v", cSubscriber.DisplayName, v.DateString, err)
-				}
+		if cSubscriber.ChouseisanHash == "" || !cSubscriber.LeftAt.IsZero() {
+			continue
+		}
+
+		// リマインド時刻は購読者のタイムゾーンで判定する
+		localNow := now.In(cSubscriber.location())
+		isRemindTime := localNow.Hour() == cSubscriber.RemindTime
+		if !isRemindTime && !cSubscriber.NotifyChanges {
+			continue
+		}
+
+		if cSubscriber.isQuietHours(localNow) {
+			// リマインドを送信しない時間帯であれば、終了時刻まで延期（出欠の変更は時間帯の終了後にまとめて通知）
+			if isRemindTime {
+				deferRemind(c, &cSubscriber, localNow)
 			}
+			continue
+		}
+
+		// ハッシュが設定されていれば、調整さんイベントをクロール
+		log.Infof(c, "Crawl chouseisan! subscriber:%v hash:%v", cSubscriber.DisplayName, cSubscriber.ChouseisanHash)
+		m := fetchScheduleMap(c, client, &cSubscriber, localNow)
+		if m == nil {
+			continue
+		}
+		if isRemindTime {
+			remindSubscriber(c, client, bot, &cSubscriber, m, localNow)
+		}
+		if cSubscriber.NotifyChanges {
+			// リマインドした直後は最新の出欠状況を送信済みなので、スナップショットの更新のみ
+			notifyChanges(c, bot, &cSubscriber, m, localNow, !isRemindTime)
 		}
 	}
 }
 
 /**
- * 調整さんをクロールして出欠を通知（cronからキックされる）
+ * 調整さんをクロールして出欠を通知（cronから毎時キックされる）
  */
 func crawlChouseisan(w http.ResponseWriter, r *http.Request) {
 	c := appengine.NewContext(r)
 	crawlChouseisanWithContext(c, urlfetch.Client(c), w, r)
 }
+
+/**
+ * 延期されたリマインドを送信
+ *
+ * 引数にContextとhttp.Clientを取るインナーメソッド
+ */
+func remindWithContext(c context.Context, client *http.Client, w http.ResponseWriter, r *http.Request) {
+	bot, err := createBotClient(c, client)
+	if err != nil {
+		return
+	}
+
+	var entity subscriber
+	mid := r.FormValue("mid")
+	key := datastore.NewKey(c, "Subscriber", mid, 0, nil)
+	if err = datastore.Get(c, key, &entity); err != nil {
+		log.Errorf(c, "Error occurred at get Subscriber entity. mid:%v err: %v", mid, err)
+		return
+	}
+	if entity.ChouseisanHash == "" {
+		log.Infof(c, "Chouseisan hash was cleared. mid:%v", mid)
+		return
+	}
+	if !entity.LeftAt.IsZero() {
+		log.Infof(c, "Subscriber has left. mid:%v", mid)
+		return
+	}
+
+	today, err := time.ParseInLocation("2006-01-02", r.FormValue("date"), entity.location())
+	if err != nil {
+		log.Errorf(c, "Invalid remind date. mid:%v date:%v err: %v", mid, r.FormValue("date"), err)
+		return
+	}
+	log.Infof(c, "Crawl chouseisan! subscriber:%v hash:%v", entity.DisplayName, entity.ChouseisanHash)
+	if m := fetchScheduleMap(c, client, &entity, today); m != nil {
+		remindSubscriber(c, client, bot, &entity, m, today)
+	}
+}
+
+/**
+ * 延期されたリマインドを送信（Task Queueからキックされる）
+ */
+func remind(w http.ResponseWriter, r *http.Request) {
+	c := appengine.NewContext(r)
+	remindWithContext(c, urlfetch.Client(c), w, r)
+}
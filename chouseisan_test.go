@@ -31,7 +31,17 @@ func TestConstructSummary(t *testing.T) {
 		"参加: 1名(電二郎)\n不参加: 2名\n不明/未入力: 4名(電一,電四郎,電五郎,電六郎)" +
 		"\n\n詳細および出欠変更は「調整さん」へ\n" +
 		"https://chouseisan.com/s?h=3f7ffd73ba174332ae05bd363eba8e71"
-	actualSummary := testdata.constructSummary("3f7ffd73ba174332ae05bd363eba8e71")
+	actualSummary := testdata.constructSummary(langJa, "3f7ffd73ba174332ae05bd363eba8e71")
+	if actualSummary != expectedSummary {
+		t.Errorf("Unmatch summary\nexpect:\n%v\nactual:\n%v", expectedSummary, actualSummary)
+	}
+
+	// 英語
+	expectedSummary = "Attendance for 10/29(土)\n\n" +
+		"Attending: 1(電二郎)\nNot attending: 2\nUndecided/no answer: 4(電一,電四郎,電五郎,電六郎)" +
+		"\n\nSee \"chouseisan\" for details or to change your answer\n" +
+		"https://chouseisan.com/s?h=3f7ffd73ba174332ae05bd363eba8e71"
+	actualSummary = testdata.constructSummary(langEn, "3f7ffd73ba174332ae05bd363eba8e71")
 	if actualSummary != expectedSummary {
 		t.Errorf("Unmatch summary\nexpect:\n%v\nactual:\n%v", expectedSummary, actualSummary)
 	}
@@ -296,17 +306,25 @@ func TestCrawlChouseisan(t *testing.T) {
 		),
 	)
 
-	// 購読者エンティティを用意しておく
+	// 購読者エンティティを用意しておく（リマインド時刻は、それぞれのタイムゾーンでの現在時刻）
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	now := time.Now()
 	entities := []subscriber{
 		{
 			MID:            "C00000000000000000000000000000000",
 			ChouseisanHash: "3f7ffd73ba174332ae05bd363eba8e71",
+			RemindTime:     now.In(tokyo).Hour(),
 		}, {
 			MID:            "R00000000000000000000000000000001",
 			ChouseisanHash: "11111111111111111111111111111111",
+			RemindTime:     now.In(tokyo).Hour(),
+			TimeZone:       "Asia/Tokyo",
 		}, {
 			MID:            "U00000000000000000000000000000002",
 			ChouseisanHash: "22222222222222222222222222222222",
+			RemindTime:     now.In(berlin).Hour(),
+			TimeZone:       "Europe/Berlin",
 		}}
 	for _, current := range entities {
 		key := datastore.NewKey(ctx, "Subscriber", current.MID, 0, nil)
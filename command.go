@@ -0,0 +1,125 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf16"
+
+	"github.com/line/line-bot-sdk-go/linebot"
+)
+
+// コマンドの接頭辞（日本語入力で打たれる全角スラッシュも受け付ける）
+var commandPrefixes = []string{"/", "／"}
+
+// 引数をまとめる引用符の開きと閉じ（スマートフォンで自動変換される全角の引用符も受け付ける）
+var commandQuotes = map[rune]rune{
+	'"': '"',
+	'“': '”',
+}
+
+/**
+ * メッセージの先頭がBOTへのメンションであれば、メンションを除いたテキストを返す
+ *
+ * メンションはWebhookのメンション先（mentionees）から、BOTのユーザIDのものを探す。位置と長さはUTF-16の単位で数える
+ * BOTのユーザIDが空、メンションの前に空白以外の文字がある場合は、メンションとしない
+ */
+func trimBotMention(text string, mentionees []*linebot.Mentionee, botUserID string) (bool, string) {
+	if botUserID == "" {
+		return false, text
+	}
+	units := utf16.Encode([]rune(text))
+	for _, m := range mentionees {
+		if m == nil || m.UserID != botUserID || m.Index < 0 || m.Length <= 0 || m.Index+m.Length > len(units) {
+			continue
+		}
+		if strings.TrimSpace(string(utf16.Decode(units[:m.Index]))) != "" {
+			continue
+		}
+		return true, string(utf16.Decode(units[m.Index+m.Length:]))
+	}
+	return false, text
+}
+
+/**
+ * メッセージがコマンドであれば、接頭辞を除いたコマンドを返す
+ *
+ * 前後の空白を除いたうえで、スラッシュ（"/"、"／"）で始まるか、BOTへのメンションで始まる（mentioned）メッセージをコマンドとする
+ * メンションに続けてスラッシュがあっても良い。メンションだけのメッセージと、メンションに続けて調整さんのURLを貼っただけのメッセージはコマンドとしない
+ */
+func parseCommand(text string, mentioned bool) (bool, string) {
+	text = strings.TrimSpace(text)
+
+	for _, prefix := range commandPrefixes {
+		if strings.HasPrefix(text, prefix) {
+			return true, strings.TrimSpace(text[len(prefix):])
+		}
+	}
+	if !mentioned || text == "" {
+		return false, ""
+	}
+	// 調整さんのURL（LINEが短縮したURLを含む）は、会話に貼られたURLとして扱う（`set chouseisan`は除く）
+	if extractChouseisanHash(text) != "" || containsLineShortURL(text) {
+		if fields := strings.Fields(text); len(fields) < 2 || fields[0] != "set" || fields[1] != "chouseisan" {
+			return false, ""
+		}
+	}
+	return true, text
+}
+
+/**
+ * コマンドの引数を空白で区切って返す
+ *
+ * 引用符（"..."、“...”）で囲んだ部分は、空白を含めてひとつの引数とする。閉じていない引用符は、末尾までをひとつの引数とする
+ */
+func splitCommandArgs(s string) []string {
+	args := []string{}
+	var (
+		current []rune
+		quoted  bool
+		closing rune
+		inArg   bool
+	)
+	for _, r := range s {
+		switch {
+		case quoted && r == closing:
+			quoted = false
+		case quoted:
+			current = append(current, r)
+		case unicode.IsSpace(r):
+			if inArg {
+				args = append(args, string(current))
+				current = nil
+				inArg = false
+			}
+		default:
+			if c, ok := commandQuotes[r]; ok {
+				quoted = true
+				closing = c
+			} else {
+				current = append(current, r)
+			}
+			inArg = true
+		}
+	}
+	if inArg {
+		args = append(args, string(current))
+	}
+	return args
+}
+
+/**
+ * 名前などの自由入力の引数を返す
+ *
+ * 全体が引用符で囲まれていれば引用符を外し、そうでなければ前後の空白を除いてそのまま返す（引数の間の空白もそのまま残す）
+ */
+func commandArg(s string) string {
+	s = strings.TrimSpace(s)
+	for open := range commandQuotes {
+		if strings.HasPrefix(s, string(open)) {
+			if args := splitCommandArgs(s); len(args) == 1 {
+				return args[0]
+			}
+		}
+	}
+	return s
+}
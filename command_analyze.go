@@ -2,12 +2,13 @@ package main
 
 import (
 	"net/http"
+	"time"
 
 	"golang.org/x/net/context"
 
 	"github.com/line/line-bot-sdk-go/linebot"
-
 	"google.golang.org/appengine"
+	"google.golang.org/appengine/datastore"
 	"google.golang.org/appengine/log"
 	"google.golang.org/appengine/urlfetch"
 )
@@ -16,11 +17,18 @@ import (
  * コマンド実行結果をリプライ
  */
 func replyMessage(c context.Context, client *http.Client, token string, message string) {
+	replyMessageWithQuickReplies(c, client, token, message, nil)
+}
+
+/**
+ * コマンド実行結果を、クイックリプライのボタンを付けてリプライ
+ */
+func replyMessageWithQuickReplies(c context.Context, client *http.Client, token string, message string, buttons []*linebot.QuickReplyButton) {
 	bot, err := createBotClient(c, client)
 	if err != nil {
 		return
 	}
-	if _, err = bot.ReplyMessage(token, linebot.NewTextMessage(message)).Do(); err != nil {
+	if err = sendReply(bot, token, withQuickReplies(newTextMessages(message), buttons)); err != nil {
 		log.Errorf(c, "Error occurred at reply-message for command. err: %v", err)
 	}
 }
@@ -34,27 +42,232 @@ func commandAnalyzeWithContext(c context.Context, client *http.Client, w http.Re
 	mid := r.FormValue("mid")
 	token := r.FormValue("replyToken")
 	text := r.FormValue("text")
+	lang := readLang(c, mid)
 
-	// `set chouseisan` command
+	// `set chouseisan` command（LINEが短縮したURLは、リダイレクト先のURLにしてから判定する）
+	if containsLineShortURL(text) {
+		text = expandLineShortURL(c, client, text)
+	}
 	if b, hash := isSetChouseisanCommand(text); b {
-		if err := writeChouseisanHash(c, mid, hash); err != nil {
-			message := "調整さんイベントの設定に失敗しました\n" + err.Error()
+		setChouseisan(c, client, mid, token, hash)
+		return
+	}
+
+	// `set name` command
+	if b, name := isSetNameCommand(text); b {
+		if err := writeName(c, mid, name); err != nil {
+			message := msg(lang, "set_name.failed") + "\n" + err.Error()
+			replyMessage(c, client, token, message)
+		} else if name == nameAuto {
+			message := msg(lang, "set_name.auto")
 			replyMessage(c, client, token, message)
 		} else {
-			message := "リマインドする調整さんイベントを設定しました"
+			message := msg(lang, "set_name.done")
 			replyMessage(c, client, token, message)
 		}
 		return
 	}
 
-	// `set name` command
-	if b, name := isSetNameCommand(text); b {
-		if err := writeName(c, mid, name); err != nil {
-			message := "グループ（もしくはトークルーム）の名前の設定に失敗しました\n" + err.Error()
+	// `set quiet` command
+	if b, start, end := isSetQuietCommand(text); b {
+		if err := writeQuietHours(c, mid, start, end); err != nil {
+			message := msg(lang, "set_quiet.failed") + "\n" + err.Error()
+			replyMessage(c, client, token, message)
+		} else if start == end {
+			message := msg(lang, "set_quiet.off")
+			replyMessage(c, client, token, message)
+		} else {
+			message := msg(lang, "set_quiet.done", start, end)
+			replyMessage(c, client, token, message)
+		}
+		return
+	}
+
+	// `set timezone` command
+	if b, name := isSetTimezoneCommand(text); b {
+		if err := writeTimeZone(c, mid, name); err != nil {
+			message := msg(lang, "set_timezone.failed") + "\n" + err.Error()
 			replyMessage(c, client, token, message)
 		} else {
-			message := "グループ（もしくはトークルーム）の名前を設定しました"
+			message := msg(lang, "set_timezone.done", name)
+			replyMessage(c, client, token, message)
+		}
+		return
+	}
+
+	// `set holiday` command
+	if b, policy := isSetHolidayCommand(text); b {
+		if err := writeHolidayPolicy(c, mid, policy); err != nil {
+			message := msg(lang, "set_holiday.failed") + "\n" + err.Error()
 			replyMessage(c, client, token, message)
+		} else {
+			message := msg(lang, "set_holiday."+policy)
+			replyMessage(c, client, token, message)
+		}
+		return
+	}
+
+	// `set deadline` command
+	if b, month, day, before := isSetDeadlineCommand(text); b {
+		if deadline, err := writeDeadline(c, mid, month, day, before); err != nil {
+			message := msg(lang, "set_deadline.failed") + "\n" + err.Error()
+			replyMessage(c, client, token, message)
+		} else if deadline.IsZero() {
+			message := msg(lang, "set_deadline.off")
+			replyMessage(c, client, token, message)
+		} else {
+			when := msg(lang, "set_deadline.before", before)
+			if before == 0 {
+				when = msg(lang, "set_deadline.same_day")
+			}
+			message := msg(lang, "set_deadline.done", deadline.Format("2006/1/2"), when)
+			replyMessage(c, client, token, message)
+		}
+		return
+	}
+
+	// `set notify changes` command
+	if b, notify := isSetNotifyChangesCommand(text); b {
+		if err := writeNotifyChanges(c, mid, notify); err != nil {
+			message := msg(lang, "set_notify.failed") + "\n" + err.Error()
+			replyMessage(c, client, token, message)
+		} else if notify {
+			message := msg(lang, "set_notify.on")
+			replyMessage(c, client, token, message)
+		} else {
+			message := msg(lang, "set_notify.off")
+			replyMessage(c, client, token, message)
+		}
+		return
+	}
+
+	// `set remindtime` command
+	if b, hour := isSetRemindTimeCommand(text); b {
+		if hour < 0 {
+			message := msg(lang, "set_remindtime.choose")
+			replyMessageWithQuickReplies(c, client, token, message, remindTimeQuickReplyButtons())
+		} else if err := writeRemindTime(c, mid, hour); err != nil {
+			message := msg(lang, "set_remindtime.failed") + "\n" + err.Error()
+			replyMessage(c, client, token, message)
+		} else {
+			message := msg(lang, "set_remindtime.done", hour)
+			replyMessage(c, client, token, message)
+		}
+		return
+	}
+
+	// `set lang` command（設定した言語でリプライする）
+	if b, newLang := isSetLangCommand(text); b {
+		if err := writeLang(c, mid, newLang); err != nil {
+			message := msg(lang, "set_lang.failed") + "\n" + err.Error()
+			replyMessage(c, client, token, message)
+		} else {
+			message := msg(newLang, "set_lang.done")
+			replyMessage(c, client, token, message)
+		}
+		return
+	}
+
+	// `show settings` command
+	if isShowSettingsCommand(text) {
+		if entity, err := readSubscriber(c, mid); err != nil {
+			message := msg(lang, "show_settings.failed") + "\n" + err.Error()
+			replyMessage(c, client, token, message)
+		} else {
+			replyMessageWithQuickReplies(c, client, token, entity.constructSettingsMessage(), menuQuickReplyButtons(lang))
+		}
+		return
+	}
+
+	// `remind now` command（当日から3日後までの出欠状況をリプライする）
+	if isRemindNowCommand(text) {
+		remindNow(c, client, mid, token, lang)
+		return
+	}
+
+	// `set template` command
+	if b, tmpl := isSetTemplateCommand(text); b {
+		if err := writeReminderTemplate(c, mid, tmpl); err != nil {
+			message := msg(lang, "set_template.failed") + "\n" + templateErrorMessage(lang, err)
+			replyMessage(c, client, token, message)
+		} else if tmpl == "" {
+			message := msg(lang, "set_template.default")
+			replyMessage(c, client, token, message)
+		} else {
+			message := msg(lang, "set_template.done")
+			replyMessage(c, client, token, message)
+		}
+		return
+	}
+
+	// `preview template` command（サンプルの日程にテンプレートを適用してリプライする）
+	if isPreviewTemplateCommand(text) {
+		if entity, err := readSubscriber(c, mid); err != nil {
+			message := msg(lang, "preview_template.failed") + "\n" + err.Error()
+			replyMessage(c, client, token, message)
+		} else if entity.ReminderTemplate == "" {
+			message := msg(lang, "preview_template.none")
+			replyMessage(c, client, token, message)
+		} else {
+			sample := sampleSchedule()
+			preview, err := renderReminderTemplate(entity.ReminderTemplate, sample.reminderTemplateData(entity.ChouseisanHash))
+			if err != nil {
+				preview = msg(lang, "preview_template.error") + "\n" + templateErrorMessage(lang, err)
+			}
+			replyMessage(c, client, token, preview)
+		}
+		return
+	}
+
+	// `show schedule` command（当日以降の日程ごとの出欠の人数をリプライする）
+	if isShowScheduleCommand(text) {
+		showSchedule(c, client, mid, token, lang)
+		return
+	}
+
+	// `help` command
+	if isHelpCommand(text) {
+		message := msg(lang, "help.message", "https://"+appengine.DefaultVersionHostname(c)+"/")
+		replyMessageWithQuickReplies(c, client, token, message, menuQuickReplyButtons(lang))
+		return
+	}
+
+	// `iam` command
+	if b, name := isIamCommand(text); b {
+		linkMember(c, client, mid, r.FormValue("uid"), token, name, lang)
+		return
+	}
+
+	// `subscribe me` command
+	if b, personal := isSubscribeMeCommand(text); b {
+		if err := writePersonalReminder(c, mid, r.FormValue("uid"), personal); err == datastore.ErrNoSuchEntity {
+			message := msg(lang, "subscribe_me.not_linked")
+			replyMessage(c, client, token, message)
+		} else if err != nil {
+			message := msg(lang, "subscribe_me.failed") + "\n" + err.Error()
+			replyMessage(c, client, token, message)
+		} else if personal {
+			// 個別通知は回答期限があるときだけ送るので、期限が未設定であれば案内する
+			var deadline time.Time
+			if entity, err := readSubscriber(c, mid); err == nil {
+				deadline = entity.Deadline
+			}
+			message := constructSubscribeMeMessage(lang, deadline)
+			replyMessage(c, client, token, message)
+		} else {
+			message := msg(lang, "subscribe_me.off")
+			replyMessage(c, client, token, message)
+		}
+		return
+	}
+
+	// `whois` command
+	if isWhoisCommand(text) {
+		if links, err := queryMemberLinks(c, mid); err != nil {
+			message := msg(lang, "whois.failed") + "\n" + err.Error()
+			replyMessage(c, client, token, message)
+		} else {
+			replyMessage(c, client, token, constructWhoisMessage(lang, links, fetchMemberNames(c, client, mid)))
 		}
 		return
 	}
@@ -71,12 +284,12 @@ func commandAnalyzeWithContext(c context.Context, client *http.Client, w http.Re
 			senderProfile, err := bot.GetProfile(uid).Do()
 			if err != nil {
 				log.Warningf(c, "Error occurred at get sender profile. uid: %v, err: %v", uid, err)
-				message = "userProfile取得失敗(" + uid + ")"
+				message = msg(lang, "uidtest.profile_failed", uid)
 			} else {
-				message = "今のメッセージ送信者は、" + senderProfile.DisplayName + "さんです"
+				message = msg(lang, "uidtest.sender", senderProfile.DisplayName)
 			}
 		} else {
-			message = "userId取得失敗(" + uid + ")"
+			message = msg(lang, "uidtest.uid_failed", uid)
 		}
 		replyMessage(c, client, token, message)
 	}
@@ -89,7 +302,7 @@ func commandAnalyzeWithContext(c context.Context, client *http.Client, w http.Re
 	}
 
 	// Reply "invalid command" message
-	message := "無効なコマンドです。\n有効なコマンドは、こちらのページをご覧ください\nhttps://" + appengine.DefaultVersionHostname(c) + "/"
+	message := msg(lang, "command.invalid", "https://"+appengine.DefaultVersionHostname(c)+"/")
 	replyMessage(c, client, token, message)
 }
 
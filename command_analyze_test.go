@@ -132,10 +132,17 @@ func TestCommandAnalyzeSetChouseisanNormally(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	// LINEへのReply Messageリクエストをモックする
+	// 調整さんへの試しの取得と、LINEへのReply Messageリクエストをモックする
 	httpmock.ActivateNonDefault(client)
 	defer httpmock.DeactivateAndReset()
 
+	httpmock.RegisterStubRequest(
+		httpmock.NewStubRequest(
+			"GET",
+			"https://chouseisan.com/schedule/List/createCsv?h="+expectedHash,
+			httpmock.NewStringResponder(200, readFile(t, "testdata/chouseisan/normally.csv")),
+		),
+	)
 	actualSendMessages := []string{} //モックに送られたリプライメッセージを保持し、後で検証する
 	httpmock.RegisterStubRequest(
 		httpmock.NewStubRequest(
@@ -166,8 +173,8 @@ func TestCommandAnalyzeSetChouseisanNormally(t *testing.T) {
 		t.Errorf("Not all stubs were called: %s", err)
 	}
 
-	//送信メッセージの検証
-	if !regexp.MustCompile("リマインドする調整さんイベントを設定しました").MatchString(actualSendMessages[0]) {
+	//送信メッセージの検証（csvから取り出したイベント名と、候補日程およびメンバーの数を返す）
+	if !strings.Contains(actualSendMessages[0], "リマインドする調整さんイベントを「調整さんリマインダテストデータ」（候補日程6件、メンバー7名）に設定しました") {
 		t.Errorf("Unmatch send message text: %v", actualSendMessages[0])
 	}
 
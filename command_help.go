@@ -0,0 +1,11 @@
+package main
+
+import "regexp"
+
+/**
+ * `help`コマンドであればtrueを返す
+ */
+func isHelpCommand(command string) bool {
+	pattern := regexp.MustCompile(`^[ \n]*help[ \n]*$`)
+	return pattern.MatchString(command)
+}
@@ -0,0 +1,30 @@
+package main
+
+import "testing"
+
+/**
+ * `help`コマンド判定
+ */
+func TestIsHelpCommand(t *testing.T) {
+	type testParameter struct {
+		text     string
+		expected bool
+	}
+	testCases := []testParameter{{
+		text:     "help",
+		expected: true,
+	}, {
+		text:     "  help \n\n", // 前後にノイズがあってもtrue
+		expected: true,
+	}, {
+		text:     "helps", //コマンド誤り
+		expected: false,
+	}}
+
+	for _, current := range testCases {
+		actual := isHelpCommand(current.text)
+		if actual != current.expected {
+			t.Errorf("Illegal return value. text:%v, returnd:%v", current.text, actual)
+		}
+	}
+}
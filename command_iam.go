@@ -0,0 +1,297 @@
+package main
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/net/context"
+	"golang.org/x/text/width"
+
+	"github.com/line/line-bot-sdk-go/linebot"
+
+	"google.golang.org/appengine/datastore"
+	"google.golang.org/appengine/log"
+)
+
+// 名前の候補として提案する最大数
+const maxNameSuggestions = 3
+
+/**
+ * `iam`コマンドであれば、指定された調整さんのメンバー名を返す（名前全体を引用符で囲める）
+ *
+ * コマンドと名前の間の全角スペースも許容する
+ */
+func isIamCommand(command string) (bool, string) {
+	pattern := regexp.MustCompile(`^[ \n　]*iam[ 　]+(.+?)[ \n　]*$`)
+	matches := pattern.FindStringSubmatch(command)
+	if len(matches) == 2 {
+		if name := commandArg(matches[1]); name != "" {
+			return true, name
+		}
+	}
+	return false, ""
+}
+
+/**
+ * `whois`コマンドであればtrueを返す
+ */
+func isWhoisCommand(command string) bool {
+	pattern := regexp.MustCompile(`^[ \n]*whois[ \n]*$`)
+	return pattern.MatchString(command)
+}
+
+/**
+ * 名前の比較用に、全角/半角を揃え、空白を除いて返す
+ */
+func normalizeName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(width.Fold.String(name)), ""))
+}
+
+/**
+ * 2つの文字列の編集距離（レーベンシュタイン距離、rune単位）を返す
+ */
+func editDistance(a string, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current := make([]int, len(rb)+1)
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = previous[j-1] + cost //置換
+			if previous[j]+1 < current[j] {
+				current[j] = previous[j] + 1 //削除
+			}
+			if current[j-1]+1 < current[j] {
+				current[j] = current[j-1] + 1 //挿入
+			}
+		}
+		previous = current
+	}
+	return previous[len(rb)]
+}
+
+/**
+ * 調整さんのメンバーの名前から、指定された名前に一致するものを返す。全角/半角、空白の違いは無視する
+ *
+ * 一致しなければ、似ている名前（一方を含む、もしくは編集距離が名前の長さの1/3以下）を近い順に返す
+ */
+func matchMemberName(name string, names []string) (string, []string) {
+	target := normalizeName(name)
+	type candidate struct {
+		name     string
+		distance int
+	}
+	candidates := []candidate{}
+	for _, v := range names {
+		normalized := normalizeName(v)
+		if normalized == target {
+			return v, nil
+		}
+		if strings.Contains(normalized, target) || strings.Contains(target, normalized) {
+			candidates = append(candidates, candidate{v, 0}) //一方を含む場合は、最も近いものとして扱う
+		} else if distance := editDistance(normalized, target); distance <= (utf8.RuneCountInString(target)+2)/3 {
+			candidates = append(candidates, candidate{v, distance})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].distance < candidates[j].distance })
+
+	suggestions := []string{}
+	for i := 0; i < len(candidates) && i < maxNameSuggestions; i++ {
+		suggestions = append(suggestions, candidates[i].name)
+	}
+	return "", suggestions
+}
+
+/**
+ * LINEユーザの表示名を取得する。グループ/トークルームでは、友だちでないメンバーも取得できるAPIを使う
+ */
+func getMemberDisplayName(c context.Context, bot *linebot.Client, mid string, uid string) string {
+	var (
+		profile *linebot.UserProfileResponse
+		err     error
+	)
+	switch {
+	case strings.HasPrefix(mid, "C"):
+		profile, err = bot.GetGroupMemberProfile(mid, uid).Do()
+	case strings.HasPrefix(mid, "R"):
+		profile, err = bot.GetRoomMemberProfile(mid, uid).Do()
+	default:
+		profile, err = bot.GetProfile(uid).Do()
+	}
+	if err != nil {
+		log.Warningf(c, "Error occurred at get member profile. mid:%v, uid:%v, err: %v", mid, uid, err)
+		return ""
+	}
+	return profile.DisplayName
+}
+
+/**
+ * メンバー紐付けエンティティのkeyを返す
+ */
+func memberLinkKey(c context.Context, mid string, uid string) *datastore.Key {
+	return datastore.NewKey(c, "MemberLink", mid+"/"+uid, 0, nil)
+}
+
+/**
+ * LINEユーザと調整さんのメンバー名の紐付けを書き込む（すでにあれば名前を上書きし、個別通知の設定は引き継ぐ）
+ */
+func writeMemberLink(c context.Context, mid string, uid string, name string, displayName string) error {
+	var entity memberLink
+	key := memberLinkKey(c, mid, uid)
+	if err := datastore.Get(c, key, &entity); err != nil && err != datastore.ErrNoSuchEntity {
+		log.Errorf(c, "Error occurred at get MemberLink entity. mid:%v uid:%v err:%v", mid, uid, err)
+		return err
+	}
+
+	entity.MID = mid
+	entity.UID = uid
+	entity.Name = name
+	entity.DisplayName = displayName
+	entity.AddTime = time.Now()
+	if _, err := datastore.Put(c, key, &entity); err != nil {
+		log.Errorf(c, "Error occurred at put MemberLink entity. mid:%v uid:%v err:%v", mid, uid, err)
+		return err
+	}
+	return nil
+}
+
+/**
+ * LINEユーザに紐付けられた調整さんのメンバー名を返す。紐付けがなければ空文字
+ */
+func readMemberName(c context.Context, mid string, uid string) (string, error) {
+	var entity memberLink
+	if err := datastore.Get(c, memberLinkKey(c, mid, uid), &entity); err != nil {
+		if err == datastore.ErrNoSuchEntity {
+			return "", nil
+		}
+		log.Errorf(c, "Error occurred at get MemberLink entity. mid:%v uid:%v err:%v", mid, uid, err)
+		return "", err
+	}
+	return entity.Name, nil
+}
+
+/**
+ * グループ/トークルームのメンバーの紐付けを、調整さんのメンバー名順で返す
+ */
+func queryMemberLinks(c context.Context, mid string) ([]memberLink, error) {
+	var entities []memberLink
+	q := datastore.NewQuery("MemberLink").Filter("MID =", mid)
+	if _, err := q.GetAll(c, &entities); err != nil {
+		log.Errorf(c, "Error occurred at query MemberLink entity. mid:%v err:%v", mid, err)
+		return nil, err
+	}
+	sort.Slice(entities, func(i, j int) bool { return entities[i].Name < entities[j].Name })
+	return entities, nil
+}
+
+/**
+ * グループ/トークルームのメンバーの紐付けを、調整さんのメンバー名からLINEユーザのidへのmapで返す
+ */
+func readMemberLinks(c context.Context, mid string) (map[string]string, error) {
+	entities, err := queryMemberLinks(c, mid)
+	if err != nil {
+		return nil, err
+	}
+
+	links := map[string]string{}
+	for _, v := range entities {
+		links[v.Name] = v.UID
+	}
+	return links, nil
+}
+
+/**
+ * 紐付けの一覧のメッセージを組み立てて返す。調整さんのメンバーが分かれば、紐付いていないメンバーも列挙する
+ */
+func constructWhoisMessage(lang string, links []memberLink, names []string) string {
+	if len(links) == 0 {
+		return msg(lang, "whois.none")
+	}
+
+	message := msg(lang, "whois.title")
+	linked := map[string]bool{}
+	for _, v := range links {
+		displayName := v.DisplayName
+		if displayName == "" {
+			displayName = msg(lang, "whois.unknown")
+		}
+		message += "\n" + v.Name + ": " + displayName
+		linked[v.Name] = true
+	}
+
+	unlinked := []string{}
+	for _, name := range names {
+		if !linked[name] {
+			unlinked = append(unlinked, name)
+		}
+	}
+	if len(unlinked) > 0 {
+		message += "\n\n" + msg(lang, "whois.unlinked", truncateNames(unlinked, ",", maxNamesLength))
+	}
+	return message
+}
+
+/**
+ * 調整さんのメンバーの名前を取得する。調整さんイベントが未設定、もしくは取得に失敗した場合はnil
+ */
+func fetchMemberNames(c context.Context, client *http.Client, mid string) []string {
+	entity, err := readSubscriber(c, mid)
+	if err != nil || entity.ChouseisanHash == "" {
+		return nil
+	}
+	m := fetchScheduleMap(c, client, entity, time.Now().In(entity.location()))
+	if m == nil {
+		return nil
+	}
+	return m.memberNames()
+}
+
+/**
+ * LINEユーザと調整さんの名前を紐付けて、結果をリプライする
+ *
+ * 調整さんの出欠表に名前が見つからなければ紐付けず、似ている名前をクイックリプライで提案する
+ */
+func linkMember(c context.Context, client *http.Client, mid string, uid string, token string, name string, lang string) {
+	if len(uid) == 0 || uid[0:1] != "U" {
+		replyMessage(c, client, token, msg(lang, "iam.no_user"))
+		return
+	}
+
+	if names := fetchMemberNames(c, client, mid); names != nil {
+		matched, suggestions := matchMemberName(name, names)
+		if matched == "" {
+			message := msg(lang, "iam.not_found", name)
+			buttons := []*linebot.QuickReplyButton{}
+			if len(suggestions) > 0 {
+				message += "\n" + msg(lang, "iam.suggest", strings.Join(suggestions, ", "))
+				for _, v := range suggestions {
+					buttons = append(buttons, newCommandQuickReplyButton(v, "iam "+v))
+				}
+			}
+			replyMessageWithQuickReplies(c, client, token, message, buttons)
+			return
+		}
+		name = matched
+	}
+
+	bot, err := createBotClient(c, client)
+	if err != nil {
+		return
+	}
+	if err := writeMemberLink(c, mid, uid, name, getMemberDisplayName(c, bot, mid, uid)); err != nil {
+		replyMessage(c, client, token, msg(lang, "iam.failed")+"\n"+err.Error())
+		return
+	}
+	replyMessage(c, client, token, msg(lang, "iam.done", name))
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"google.golang.org/appengine"
+	"google.golang.org/appengine/aetest"
+)
+
+/**
+ * `iam`コマンド判定と指定された名前の取り出し
+ */
+func TestIsIamCommand(t *testing.T) {
+	type testParameter struct {
+		text         string
+		expectedIs   bool
+		expectedName string
+	}
+	testCases := []testParameter{{
+		text:         "iam 電次郎",
+		expectedIs:   true,
+		expectedName: "電次郎",
+	}, {
+		text:         "  iam 電次郎\n\n", // 前後にノイズがあってもtrue
+		expectedIs:   true,
+		expectedName: "電次郎",
+	}, {
+		text:         `iam "Denjiro Chousei"`, // 空白を含む名前は引用符で囲む
+		expectedIs:   true,
+		expectedName: "Denjiro Chousei",
+	}, {
+		text:         "iam　電 次郎", // 全角スペース、引用符なしの名前の空白
+		expectedIs:   true,
+		expectedName: "電 次郎",
+	}, {
+		text:         "iam", // 名前の指定なし
+		expectedIs:   false,
+		expectedName: "",
+	}}
+
+	for _, current := range testCases {
+		actualIs, actualName := isIamCommand(current.text)
+		if actualIs != current.expectedIs {
+			t.Errorf("Illegal return value. text:%v, returnd:%v", current.text, actualIs)
+		}
+		if actualName != current.expectedName {
+			t.Errorf("Illegal return value. text:%v, returnd:%v", current.text, actualName)
+		}
+	}
+}
+
+/**
+ * `whois`コマンド判定
+ */
+func TestIsWhoisCommand(t *testing.T) {
+	type testParameter struct {
+		text     string
+		expected bool
+	}
+	testCases := []testParameter{{
+		text:     "whois",
+		expected: true,
+	}, {
+		text:     "  whois \n\n", // 前後にノイズがあってもtrue
+		expected: true,
+	}, {
+		text:     "who", //コマンド誤り
+		expected: false,
+	}}
+
+	for _, current := range testCases {
+		actual := isWhoisCommand(current.text)
+		if actual != current.expected {
+			t.Errorf("Illegal return value. text:%v, returnd:%v", current.text, actual)
+		}
+	}
+}
+
+/**
+ * 調整さんのメンバー名との照合と、似ている名前の提案
+ */
+func TestMatchMemberName(t *testing.T) {
+	names := []string{"電一", "電次郎", "電三太郎", "電四郎", "Den Goro"}
+	type testParameter struct {
+		name                string
+		expectedMatched     string
+		expectedSuggestions string
+	}
+	testCases := []testParameter{{
+		name:            "電次郎",
+		expectedMatched: "電次郎",
+	}, {
+		name:            "ｄｅｎ　ｇｏｒｏ", // 全角/半角、空白の違いは無視する
+		expectedMatched: "Den Goro",
+	}, {
+		name:                "電二郎", // 1文字違い
+		expectedSuggestions: "電次郎,電四郎",
+	}, {
+		name:                "電三", // 一部分（含むものを優先）
+		expectedSuggestions: "電三太郎,電一",
+	}, {
+		name:                "山田花子", // 似ている名前なし
+		expectedSuggestions: "",
+	}}
+
+	for _, current := range testCases {
+		actualMatched, actualSuggestions := matchMemberName(current.name, names)
+		if actualMatched != current.expectedMatched {
+			t.Errorf("Illegal return value. name:%v, returnd:%v", current.name, actualMatched)
+		}
+		if strings.Join(actualSuggestions, ",") != current.expectedSuggestions {
+			t.Errorf("Illegal return value. name:%v, returnd:%v", current.name, actualSuggestions)
+		}
+	}
+}
+
+/**
+ * 紐付けの一覧のメッセージの組み立て
+ */
+func TestConstructWhoisMessage(t *testing.T) {
+	links := []memberLink{
+		{Name: "電三太郎", DisplayName: "でんさんたろう"},
+		{Name: "電次郎", DisplayName: ""},
+	}
+	expected := "調整さんの名前を登録したメンバー\n電三太郎: でんさんたろう\n電次郎: (不明)\n\n未登録: 電一,電四郎"
+	if actual := constructWhoisMessage(langJa, links, []string{"電一", "電次郎", "電三太郎", "電四郎"}); actual != expected {
+		t.Errorf("Unmatch whois message\nexpect:\n%v\nactual:\n%v", expected, actual)
+	}
+}
+
+/**
+ * データストアにメンバーの紐付けを書き込み、読み出す関数のテスト（正常系）
+ */
+func TestWriteMemberLinkNormally(t *testing.T) {
+	opt := aetest.Options{StronglyConsistentDatastore: true} //データストアに即反映
+	instance, err := aetest.NewInstance(&opt)
+	if err != nil {
+		t.Fatalf("Failed to create aetest instance: %v", err)
+	}
+	defer instance.Close()
+
+	// Contextが必要なので、ダミーのhttp.Request
+	req, err := instance.NewRequest("POST", "/task/analyzecommand", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := appengine.NewContext(req)
+
+	mid := "C00000000000000000000000000000000"
+	uid := "U00000000000000000000000000000001"
+
+	// 紐付けがなければ空文字
+	if name, err := readMemberName(c, mid, uid); err != nil || name != "" {
+		t.Fatalf("Unexpected member name. name='%v' err:%v", name, err)
+	}
+
+	// execute（2回目で上書きされること）
+	if err := writeMemberLink(c, mid, uid, "電一", "でんいち"); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeMemberLink(c, mid, uid, "電次郎", "でんじろう"); err != nil {
+		t.Fatal(err)
+	}
+
+	name, err := readMemberName(c, mid, uid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "電次郎" {
+		t.Errorf("Unmatch member name. name='%v'", name)
+	}
+}
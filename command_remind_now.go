@@ -0,0 +1,80 @@
+package main
+
+import (
+	"net/http"
+	"regexp"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/line/line-bot-sdk-go/linebot"
+
+	"google.golang.org/appengine/log"
+)
+
+/**
+ * `remind now`コマンドであればtrueを返す
+ */
+func isRemindNowCommand(command string) bool {
+	pattern := regexp.MustCompile(`^[ \n]*remind now[ \n]*$`)
+	return pattern.MatchString(command)
+}
+
+/**
+ * 当日からN日後までの日程を、開催日順に返す
+ */
+func (m scheduleMap) upcomingSchedules(today time.Time, days int) []schedule {
+	to := time.Date(today.Year(), today.Month(), today.Day()+days, 0, 0, 0, 0, today.Location())
+
+	result := []schedule{}
+	for _, s := range m.futureSchedules(today) {
+		if !s.Date.After(to) {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+/**
+ * 当日から3日後までの日程の出欠状況を、Flex Messageでリプライする
+ */
+func remindNow(c context.Context, client *http.Client, mid string, token string, lang string) {
+	entity, err := readSubscriber(c, mid)
+	if err != nil {
+		replyMessage(c, client, token, msg(lang, "remind_now.failed")+"\n"+err.Error())
+		return
+	}
+	if entity.ChouseisanHash == "" {
+		replyMessage(c, client, token, msg(lang, "command.no_event"))
+		return
+	}
+
+	m := fetchScheduleMap(c, client, entity, time.Now().In(entity.location()))
+	if m == nil {
+		replyMessage(c, client, token, msg(lang, "command.fetch_failed"))
+		return
+	}
+	schedules := m.upcomingSchedules(time.Now().In(entity.location()), 3)
+	if len(schedules) == 0 {
+		replyMessage(c, client, token, msg(lang, "remind_now.none"))
+		return
+	}
+
+	messages := []linebot.SendingMessage{}
+	for _, v := range schedules {
+		message, err := v.constructReminderMessageFor(c, entity)
+		if err != nil {
+			log.Errorf(c, "Error occurred at construct reminder message. subscriber:%v, date:%v, err: %v", entity.DisplayName, v.DateString, err)
+			continue
+		}
+		messages = append(messages, message)
+	}
+
+	bot, err := createBotClient(c, client)
+	if err != nil {
+		return
+	}
+	if err = sendReply(bot, token, messages); err != nil {
+		log.Errorf(c, "Error occurred at reply-message for remind now. mid:%v, err: %v", mid, err)
+	}
+}
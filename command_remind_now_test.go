@@ -0,0 +1,50 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+/**
+ * `remind now`コマンド判定
+ */
+func TestIsRemindNowCommand(t *testing.T) {
+	type testParameter struct {
+		text     string
+		expected bool
+	}
+	testCases := []testParameter{{
+		text:     "remind now",
+		expected: true,
+	}, {
+		text:     "  remind now \n\n", // 前後にノイズがあってもtrue
+		expected: true,
+	}, {
+		text:     "remind", //コマンド誤り
+		expected: false,
+	}}
+
+	for _, current := range testCases {
+		actual := isRemindNowCommand(current.text)
+		if actual != current.expected {
+			t.Errorf("Illegal return value. text:%v, returnd:%v", current.text, actual)
+		}
+	}
+}
+
+/**
+ * 当日からN日後までの日程の抽出
+ */
+func TestUpcomingSchedules(t *testing.T) {
+	tz, _ := time.LoadLocation("Asia/Tokyo")
+	m := scheduleMap{}
+	for _, day := range []int{19, 20, 23, 24, 25} {
+		date := time.Date(2016, time.December, day, 0, 0, 0, 0, tz)
+		m[date.String()] = schedule{Date: date}
+	}
+
+	actual := m.upcomingSchedules(time.Date(2016, time.December, 20, 8, 0, 0, 0, tz), 3)
+	if len(actual) != 2 || actual[0].Date.Day() != 20 || actual[1].Date.Day() != 23 {
+		t.Errorf("Unmatch upcoming schedules: %v", actual)
+	}
+}
@@ -1,7 +1,10 @@
 package main
 
 import (
+	"net/http"
 	"regexp"
+	"strings"
+	"time"
 
 	"google.golang.org/appengine/datastore"
 	"google.golang.org/appengine/log"
@@ -9,20 +12,73 @@ import (
 	"golang.org/x/net/context"
 )
 
+/**
+ * テキストに含まれる調整さんイベントのURLから、調整さんハッシュを取り出す。見つからなければ空文字
+ *
+ * http/https、www.の有無、スキームの省略、/s?h=と/schedule/List?h=、前後の余分なクエリパラメータを許容する
+ * ハッシュは英数字のみなので、URLの直後の句読点や括弧（「。」「)」など）は含まない
+ * ドメインの前は、テキストの先頭、スキーム、英数字・"."・"-"・"/"以外の文字に限る（"evilchouseisan.com"や"example.com/chouseisan.com"は対象外）
+ * LINEが短縮したURLは、先にexpandLineShortURLでリダイレクト先のURLに置き換えておく
+ */
+func extractChouseisanHash(text string) string {
+	pattern := regexp.MustCompile(`(?:^|[^\w./-]|https?://)(?:www\.)?chouseisan\.com/(?:s|schedule/List)\?(?:[^\s#]*?&)?h=(\w+)`)
+	matches := pattern.FindStringSubmatch(text)
+	if len(matches) == 2 {
+		return matches[1]
+	}
+	return ""
+}
+
+// LINEが短縮したURL
+var lineShortURLPattern = regexp.MustCompile(`https?://lin\.ee/[\w-]+`)
+
+/**
+ * テキストにLINEが短縮したURLが含まれていればtrueを返す
+ */
+func containsLineShortURL(text string) bool {
+	return lineShortURLPattern.MatchString(text)
+}
+
+/**
+ * テキストに含まれるLINEが短縮したURLを、リダイレクト先が調整さんのURLであれば置き換えて返す
+ *
+ * リダイレクト先を取得できない、調整さんのURLでなければ、そのままにする
+ */
+func expandLineShortURL(c context.Context, client *http.Client, text string) string {
+	return lineShortURLPattern.ReplaceAllStringFunc(text, func(short string) string {
+		res, err := client.Get(short)
+		if err != nil {
+			log.Warningf(c, "Error occurred at resolve short URL. url:%v err:%v", short, err)
+			return short
+		}
+		defer res.Body.Close()
+		if resolved := res.Request.URL.String(); extractChouseisanHash(resolved) != "" {
+			return resolved
+		}
+		return short
+	})
+}
+
 /**
  * `set chouseisan`コマンドであれば、調整さんハッシュを返す
+ *
+ * コマンドとURLの間の全角スペースも許容する
  */
 func isSetChouseisanCommand(command string) (bool, string) {
-	pattern := regexp.MustCompile(`^[ \n]*set chouseisan https:\/\/chouseisan\.com\/s\?h=(\w+)[ \n]*$`)
+	command = strings.Replace(command, "　", " ", -1)
+	pattern := regexp.MustCompile(`^[ \n]*set chouseisan[ \n]+(\S+)[ \n]*$`)
 	matches := pattern.FindStringSubmatch(command)
-	if len(matches) == 2 {
-		return true, matches[1]
+	if len(matches) != 2 {
+		return false, ""
+	}
+	if hash := extractChouseisanHash(matches[1]); hash != "" {
+		return true, hash
 	}
 	return false, ""
 }
 
 /**
- * 購読者エンティティに、調整さんハッシュを書き込む
+ * 購読者エンティティに、調整さんハッシュを書き込む。初期設定中であれば、初期設定を終える
  */
 func writeChouseisanHash(c context.Context, mid string, hash string) error {
 	var entity subscriber
@@ -34,9 +90,78 @@ func writeChouseisanHash(c context.Context, mid string, hash string) error {
 	}
 
 	entity.ChouseisanHash = hash
+	entity.Onboarding = ""
 	if _, err := datastore.Put(c, key, &entity); err != nil {
 		log.Errorf(c, "Error occurred at put Subscriber entity. mid:%v err:%v", mid, err)
 		return err
 	}
 	return nil
 }
+
+/**
+ * 調整さんハッシュのイベントを試しに取得して、csvをパースした結果を返す
+ *
+ * 候補日程が1件も読み取れなければ（イベントのページなどcsv以外が返ってきた場合も含む）、errChouseisanUnparsableを返す
+ */
+func fetchChouseisanEvent(c context.Context, client *http.Client, current *subscriber, hash string) (scheduleMap, error) {
+	m, err := fetchChouseisanCsv(c, client, hash, time.Now().In(current.location()))
+	if err != nil {
+		return nil, err
+	}
+	if len(m) == 0 {
+		return nil, errChouseisanUnparsable
+	}
+	return m, nil
+}
+
+/**
+ * 調整さんイベントの名前と、候補日程およびメンバーの数を組み立てて返す。名前がなければURLで示す
+ */
+func (m scheduleMap) constructEventSummary(lang string, hash string) string {
+	title := m.eventTitle()
+	if title == "" {
+		title = "https://chouseisan.com/s?h=" + hash
+	}
+	return msg(lang, "set_chouseisan.event", title, len(m), len(m.memberNames()))
+}
+
+/**
+ * 調整さんイベントを試しに取得できたら購読者に設定して、イベント名と候補日程、メンバーの数をリプライする
+ *
+ * イベントが見つからない、もしくは出欠表を読み取れなければ、理由をリプライして設定しない
+ *
+ * 初期設定中であれば、デフォルトのリマインドのタイミングも添えて、リマインド時刻をクイックリプライのボタンで変更できるようにする
+ */
+func setChouseisan(c context.Context, client *http.Client, mid string, token string, hash string) {
+	entity, err := readSubscriber(c, mid)
+	if err != nil {
+		replyMessage(c, client, token, msg(readLang(c, mid), "set_chouseisan.failed")+"\n"+err.Error())
+		return
+	}
+	lang := entity.lang()
+
+	m, err := fetchChouseisanEvent(c, client, entity, hash)
+	if err != nil {
+		log.Warningf(c, "Invalid chouseisan event. mid:%v hash:%v err:%v", mid, hash, err)
+		key := "set_chouseisan.fetch_failed"
+		if err == errChouseisanNotFound {
+			key = "set_chouseisan.not_found"
+		} else if err == errChouseisanUnparsable {
+			key = "set_chouseisan.unparsable"
+		}
+		replyMessage(c, client, token, msg(lang, key, "https://chouseisan.com/s?h="+hash))
+		return
+	}
+	if err := writeChouseisanHash(c, mid, hash); err != nil {
+		replyMessage(c, client, token, msg(lang, "set_chouseisan.failed")+"\n"+err.Error())
+		return
+	}
+
+	log.Infof(c, "Set chouseisan event. mid:%v hash:%v title:%v", mid, hash, m.eventTitle())
+	event := m.constructEventSummary(lang, hash)
+	if entity.Onboarding != "" {
+		replyMessageWithQuickReplies(c, client, token, entity.constructOnboardingConfirmMessage(event), remindTimeQuickReplyButtons())
+	} else {
+		replyMessage(c, client, token, msg(lang, "set_chouseisan.done", event))
+	}
+}
@@ -1,11 +1,15 @@
 package main
 
 import (
+	"net/http"
 	"testing"
+	"time"
 
+	"github.com/thingful/httpmock"
 	"google.golang.org/appengine"
 	"google.golang.org/appengine/aetest"
 	"google.golang.org/appengine/datastore"
+	"google.golang.org/appengine/urlfetch"
 )
 
 /**
@@ -25,6 +29,42 @@ func TestIsSetChouseisanCommandNormally(t *testing.T) {
 		text:         "  set chouseisan https://chouseisan.com/s?h=3f7ffd73ba174332ae05bd363eba8e71\n\n", // 前後にノイズがあってもtrue
 		expectedIs:   true,
 		expectedHash: "3f7ffd73ba174332ae05bd363eba8e71",
+	}, {
+		text:         "set chouseisan http://www.chouseisan.com/s?h=3f7ffd73ba174332ae05bd363eba8e71", // http、www.付き
+		expectedIs:   true,
+		expectedHash: "3f7ffd73ba174332ae05bd363eba8e71",
+	}, {
+		text:         "set chouseisan chouseisan.com/s?h=3f7ffd73ba174332ae05bd363eba8e71", // スキームなし
+		expectedIs:   true,
+		expectedHash: "3f7ffd73ba174332ae05bd363eba8e71",
+	}, {
+		text:         "set chouseisan https://chouseisan.com/schedule/List?utm_source=line&h=3f7ffd73ba174332ae05bd363eba8e71&x=1", // 出欠表のURL、余分なクエリパラメータ
+		expectedIs:   true,
+		expectedHash: "3f7ffd73ba174332ae05bd363eba8e71",
+	}, {
+		text:         "set chouseisan　https://chouseisan.com/s?h=3f7ffd73ba174332ae05bd363eba8e71。", // 全角スペース、末尾の句読点
+		expectedIs:   true,
+		expectedHash: "3f7ffd73ba174332ae05bd363eba8e71",
+	}, {
+		text:         "set chouseisan https://example.com/s?h=3f7ffd73ba174332ae05bd363eba8e71", // 調整さん以外のURL
+		expectedIs:   false,
+		expectedHash: "",
+	}, {
+		text:         "set chouseisan https://evilchouseisan.com/s?h=3f7ffd73ba174332ae05bd363eba8e71", // 調整さんに似たドメイン
+		expectedIs:   false,
+		expectedHash: "",
+	}, {
+		text:         "set chouseisan evilchouseisan.com/s?h=3f7ffd73ba174332ae05bd363eba8e71", // スキームなしの似たドメイン
+		expectedIs:   false,
+		expectedHash: "",
+	}, {
+		text:         "set chouseisan https://example.com/chouseisan.com/s?h=3f7ffd73ba174332ae05bd363eba8e71", // パスに含まれるだけ
+		expectedIs:   false,
+		expectedHash: "",
+	}, {
+		text:         "set chouseisan https://chouseisan.com/s?x=3f7ffd73ba174332ae05bd363eba8e71", // ハッシュなし
+		expectedIs:   false,
+		expectedHash: "",
 	}, {
 		text:         "set hash https://chouseisan.com/s?h=3f7ffd73ba174332ae05bd363eba8e71", // コマンド誤り
 		expectedIs:   false,
@@ -86,3 +126,164 @@ func TestWriteChouseisanHashNormally(t *testing.T) {
 		t.Errorf("Unmatch entitiy's hash. hash='%v'", actualEntity.ChouseisanHash)
 	}
 }
+
+/**
+ * 調整さんイベントの概要（イベント名と、候補日程およびメンバーの数）
+ */
+func TestConstructEventSummary(t *testing.T) {
+	tz, _ := time.LoadLocation("Asia/Tokyo")
+	m := scheduleMap{}
+	for _, day := range []int{20, 24} {
+		date := time.Date(2016, time.December, day, 0, 0, 0, 0, tz)
+		m[date.String()] = schedule{Date: date, EventTitle: "忘年会", Names: []string{"電一", "電次郎", "電三太郎"}}
+	}
+
+	expected := "「忘年会」（候補日程2件、メンバー3名）"
+	if actual := m.constructEventSummary(langJa, "3f7ffd73ba174332ae05bd363eba8e71"); actual != expected {
+		t.Errorf("Unmatch event summary. expected:%v, actual:%v", expected, actual)
+	}
+	expected = "\"忘年会\" (2 candidate dates, 3 members)"
+	if actual := m.constructEventSummary(langEn, "3f7ffd73ba174332ae05bd363eba8e71"); actual != expected {
+		t.Errorf("Unmatch event summary. expected:%v, actual:%v", expected, actual)
+	}
+}
+
+/**
+ * 調整さんイベントの試しの取得（存在しない、csvとして読み取れないイベントはエラー）
+ */
+func TestFetchChouseisanEvent(t *testing.T) {
+	opt := aetest.Options{StronglyConsistentDatastore: true} //データストアに即反映
+	instance, err := aetest.NewInstance(&opt)
+	if err != nil {
+		t.Fatalf("Failed to create aetest instance: %v", err)
+	}
+	defer instance.Close()
+
+	// Contextが必要なので、ダミーのhttp.Request
+	req, err := instance.NewRequest("POST", "/task/analyzecommand", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := appengine.NewContext(req)
+	client := urlfetch.Client(c)
+
+	// 調整さんへのリクエストをモックする
+	httpmock.ActivateNonDefault(client)
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterStubRequest(
+		httpmock.NewStubRequest(
+			"GET",
+			"https://chouseisan.com/schedule/List/createCsv?h=3f7ffd73ba174332ae05bd363eba8e71",
+			httpmock.NewStringResponder(200, readFile(t, "testdata/chouseisan/normally.csv")),
+		),
+	)
+	httpmock.RegisterStubRequest(
+		httpmock.NewStubRequest(
+			"GET",
+			"https://chouseisan.com/schedule/List/createCsv?h=00000000000000000000000000000000",
+			httpmock.NewStringResponder(404, "Not Found"),
+		),
+	)
+	httpmock.RegisterStubRequest(
+		httpmock.NewStubRequest(
+			"GET",
+			"https://chouseisan.com/schedule/List/createCsv?h=11111111111111111111111111111111",
+			httpmock.NewStringResponder(200, "<html><body>\"調整さん\"</body></html>"),
+		),
+	)
+
+	type testParameter struct {
+		hash          string
+		expectedErr   error
+		expectedDates int
+	}
+	testCases := []testParameter{{
+		hash:          "3f7ffd73ba174332ae05bd363eba8e71",
+		expectedErr:   nil,
+		expectedDates: 6,
+	}, {
+		hash:        "00000000000000000000000000000000", // 存在しないイベント
+		expectedErr: errChouseisanNotFound,
+	}, {
+		hash:        "11111111111111111111111111111111", // csv以外が返ってきた
+		expectedErr: errChouseisanUnparsable,
+	}}
+
+	current := &subscriber{TimeZone: "Asia/Tokyo"}
+	for _, v := range testCases {
+		m, err := fetchChouseisanEvent(c, client, current, v.hash)
+		if err != v.expectedErr {
+			t.Errorf("Illegal return value. hash:%v, returnd:%v", v.hash, err)
+		}
+		if len(m) != v.expectedDates {
+			t.Errorf("Unmatch number of dates. hash:%v, returnd:%v", v.hash, len(m))
+		}
+	}
+}
+
+/**
+ * LINEが短縮したURLを、リダイレクト先の調整さんのURLに置き換える
+ */
+func TestExpandLineShortURL(t *testing.T) {
+	opt := aetest.Options{StronglyConsistentDatastore: true} //データストアに即反映
+	instance, err := aetest.NewInstance(&opt)
+	if err != nil {
+		t.Fatalf("Failed to create aetest instance: %v", err)
+	}
+	defer instance.Close()
+
+	// Contextが必要なので、ダミーのhttp.Request
+	req, err := instance.NewRequest("POST", "/task/analyzecommand", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := appengine.NewContext(req)
+	client := urlfetch.Client(c)
+
+	// 短縮したURLのリダイレクトと、リダイレクト先へのリクエストをモックする
+	redirectTo := func(location string) httpmock.Responder {
+		return func(req *http.Request) (*http.Response, error) {
+			res := httpmock.NewStringResponse(http.StatusFound, "")
+			res.Header = http.Header{"Location": {location}}
+			return res, nil
+		}
+	}
+	httpmock.ActivateNonDefault(client)
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterStubRequest(
+		httpmock.NewStubRequest("GET", "https://lin.ee/AbCdEfG", redirectTo("https://chouseisan.com/s?h=3f7ffd73ba174332ae05bd363eba8e71")),
+	)
+	httpmock.RegisterStubRequest(
+		httpmock.NewStubRequest("GET", "https://chouseisan.com/s?h=3f7ffd73ba174332ae05bd363eba8e71", httpmock.NewStringResponder(200, "<html></html>")),
+	)
+	httpmock.RegisterStubRequest(
+		httpmock.NewStubRequest("GET", "https://lin.ee/HiJkLmN", redirectTo("https://example.com/")),
+	)
+	httpmock.RegisterStubRequest(
+		httpmock.NewStubRequest("GET", "https://example.com/", httpmock.NewStringResponder(200, "<html></html>")),
+	)
+
+	type testParameter struct {
+		text         string
+		expectedHash string
+	}
+	testCases := []testParameter{{
+		text:         "set chouseisan https://lin.ee/AbCdEfG",
+		expectedHash: "3f7ffd73ba174332ae05bd363eba8e71",
+	}, {
+		text:         "set chouseisan https://lin.ee/HiJkLmN", // 調整さん以外へのリダイレクト
+		expectedHash: "",
+	}}
+
+	for _, current := range testCases {
+		_, actual := isSetChouseisanCommand(expandLineShortURL(c, client, current.text))
+		if actual != current.expectedHash {
+			t.Errorf("Illegal return value. text:%v, returnd:%v", current.text, actual)
+		}
+	}
+
+	// スタブがすべて呼ばれたことを検証
+	if err := httpmock.AllStubsCalled(); err != nil {
+		t.Errorf("Not all stubs were called: %s", err)
+	}
+}
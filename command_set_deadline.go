@@ -0,0 +1,83 @@
+package main
+
+import (
+	"regexp"
+	"strconv"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/appengine/datastore"
+	"google.golang.org/appengine/log"
+)
+
+// 回答期限の何日前に未回答のメンバーをリマインドするか（`set deadline`で省略した場合）
+const defaultDeadlineBefore = 1
+
+/**
+ * `set deadline`コマンドであれば、回答期限の月・日と、何日前にリマインドするかを返す
+ *
+ * `set deadline off`の場合は、月・日とも0を返す（期限なし）
+ * 存在しない日付（2/30、4/31など）はfalse。年は分からないので、2/29は受け付ける
+ */
+func isSetDeadlineCommand(command string) (bool, int, int, int) {
+	if regexp.MustCompile(`^[ \n]*set deadline off[ \n]*$`).MatchString(command) {
+		return true, 0, 0, 0
+	}
+
+	pattern := regexp.MustCompile(`^[ \n]*set deadline (\d{1,2})/(\d{1,2})(?: (\d{1,2}))?[ \n]*$`)
+	matches := pattern.FindStringSubmatch(command)
+	if len(matches) != 4 {
+		return false, 0, 0, 0
+	}
+	month, _ := strconv.Atoi(matches[1])
+	day, _ := strconv.Atoi(matches[2])
+	// time.Dateは存在しない日付を翌月に繰り越すため、月・日が変わったら存在しない日付とする（うるう年で確かめる）
+	if date := time.Date(2016, time.Month(month), day, 0, 0, 0, 0, time.UTC); int(date.Month()) != month || date.Day() != day {
+		return false, 0, 0, 0
+	}
+	before := defaultDeadlineBefore
+	if len(matches[3]) > 0 {
+		before, _ = strconv.Atoi(matches[3])
+	}
+	return true, month, day, before
+}
+
+/**
+ * 月・日から、today以降で直近の回答期限の日付を返す（過ぎている日付は来年として扱う）
+ *
+ * 2/29は、その日がある直近のうるう年にする
+ */
+func deadlineDate(month int, day int, today time.Time) time.Time {
+	from := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, today.Location())
+	for year := today.Year(); ; year++ {
+		deadline := time.Date(year, time.Month(month), day, 0, 0, 0, 0, today.Location())
+		if deadline.Day() == day && !deadline.Before(from) {
+			return deadline
+		}
+	}
+}
+
+/**
+ * 購読者エンティティに、回答期限を書き込む。月に0を指定すると期限を解除する
+ */
+func writeDeadline(c context.Context, mid string, month int, day int, before int) (time.Time, error) {
+	var entity subscriber
+
+	key := datastore.NewKey(c, "Subscriber", mid, 0, nil)
+	if err := datastore.Get(c, key, &entity); err != nil {
+		log.Errorf(c, "Error occurred at get Subscriber entity. mid:%v err:%v", mid, err)
+		return time.Time{}, err
+	}
+
+	if month == 0 {
+		entity.Deadline = time.Time{}
+	} else {
+		entity.Deadline = deadlineDate(month, day, time.Now().In(entity.location()))
+	}
+	entity.DeadlineBefore = before
+	if _, err := datastore.Put(c, key, &entity); err != nil {
+		log.Errorf(c, "Error occurred at put Subscriber entity. mid:%v err:%v", mid, err)
+		return time.Time{}, err
+	}
+	return entity.Deadline, nil
+}
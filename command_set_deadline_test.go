@@ -0,0 +1,151 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"google.golang.org/appengine"
+	"google.golang.org/appengine/aetest"
+	"google.golang.org/appengine/datastore"
+)
+
+/**
+ * `set deadline`コマンド判定と期限の取り出し
+ */
+func TestIsSetDeadlineCommand(t *testing.T) {
+	type testParameter struct {
+		text           string
+		expectedIs     bool
+		expectedMonth  int
+		expectedDay    int
+		expectedBefore int
+	}
+	testCases := []testParameter{{
+		text:           "set deadline 12/20",
+		expectedIs:     true,
+		expectedMonth:  12,
+		expectedDay:    20,
+		expectedBefore: 1, // 省略時は1日前
+	}, {
+		text:           "  set deadline 1/5 3\n\n", // 前後にノイズがあってもtrue
+		expectedIs:     true,
+		expectedMonth:  1,
+		expectedDay:    5,
+		expectedBefore: 3,
+	}, {
+		text:           "set deadline off", // 解除
+		expectedIs:     true,
+		expectedMonth:  0,
+		expectedDay:    0,
+		expectedBefore: 0,
+	}, {
+		text:           "set deadline 13/1", // 範囲外の月
+		expectedIs:     false,
+		expectedMonth:  0,
+		expectedDay:    0,
+		expectedBefore: 0,
+	}, {
+		text:           "set deadline 2/30", // 存在しない日付（3/2にしない）
+		expectedIs:     false,
+		expectedMonth:  0,
+		expectedDay:    0,
+		expectedBefore: 0,
+	}, {
+		text:           "set deadline 4/31", // 存在しない日付（5/1にしない）
+		expectedIs:     false,
+		expectedMonth:  0,
+		expectedDay:    0,
+		expectedBefore: 0,
+	}, {
+		text:           "set deadline 2/29", // うるう日は受け付ける
+		expectedIs:     true,
+		expectedMonth:  2,
+		expectedDay:    29,
+		expectedBefore: 1,
+	}, {
+		text:           "set deadline tomorrow", // 書式誤り
+		expectedIs:     false,
+		expectedMonth:  0,
+		expectedDay:    0,
+		expectedBefore: 0,
+	}}
+
+	for _, current := range testCases {
+		actualIs, actualMonth, actualDay, actualBefore := isSetDeadlineCommand(current.text)
+		if actualIs != current.expectedIs {
+			t.Errorf("Illegal return value. text:%v, returnd:%v", current.text, actualIs)
+		}
+		if actualMonth != current.expectedMonth || actualDay != current.expectedDay || actualBefore != current.expectedBefore {
+			t.Errorf("Illegal return value. text:%v, returnd:%v/%v %v", current.text, actualMonth, actualDay, actualBefore)
+		}
+	}
+}
+
+/**
+ * 回答期限の日付（過ぎている日付は来年として扱う）
+ */
+func TestDeadlineDate(t *testing.T) {
+	tz, _ := time.LoadLocation("Asia/Tokyo")
+	today := time.Date(2016, time.December, 1, 8, 0, 0, 0, tz)
+
+	if actual := deadlineDate(12, 20, today); !actual.Equal(time.Date(2016, time.December, 20, 0, 0, 0, 0, tz)) {
+		t.Errorf("Illegal deadline date. returnd:%v", actual)
+	}
+	if actual := deadlineDate(12, 1, today); !actual.Equal(time.Date(2016, time.December, 1, 0, 0, 0, 0, tz)) {
+		t.Errorf("Illegal deadline date (today). returnd:%v", actual)
+	}
+	if actual := deadlineDate(1, 5, today); !actual.Equal(time.Date(2017, time.January, 5, 0, 0, 0, 0, tz)) {
+		t.Errorf("Illegal deadline date (next year). returnd:%v", actual)
+	}
+	if actual := deadlineDate(2, 29, today); !actual.Equal(time.Date(2020, time.February, 29, 0, 0, 0, 0, tz)) {
+		t.Errorf("Illegal deadline date (leap day). returnd:%v", actual)
+	}
+}
+
+/**
+ * データストアに回答期限を書き込む関数のテスト（正常系）
+ */
+func TestWriteDeadlineNormally(t *testing.T) {
+	opt := aetest.Options{StronglyConsistentDatastore: true} //データストアに即反映
+	instance, err := aetest.NewInstance(&opt)
+	if err != nil {
+		t.Fatalf("Failed to create aetest instance: %v", err)
+	}
+	defer instance.Close()
+
+	// Contextが必要なので、ダミーのhttp.Request
+	req, err := instance.NewRequest("POST", "/task/analyzecommand", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := appengine.NewContext(req)
+
+	mid := "C00000000000000000000000000000000"
+
+	// 更新される購読者エンティティを用意しておく
+	entity := subscriber{
+		MID: mid,
+	}
+	key := datastore.NewKey(c, "Subscriber", mid, 0, nil)
+	if _, err = datastore.Put(c, key, &entity); err != nil {
+		t.Fatal(err)
+	}
+
+	// execute
+	expectedDeadline, err := writeDeadline(c, mid, 12, 20, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// データストアに期限が書き込まれていること
+	var actualEntity subscriber
+	if err = datastore.Get(c, key, &actualEntity); err != nil {
+		t.Fatal(err)
+	}
+	if !actualEntity.Deadline.Equal(expectedDeadline) || actualEntity.DeadlineBefore != 2 {
+		t.Errorf("Unmatch entitiy's deadline. deadline='%v', before='%v'", actualEntity.Deadline, actualEntity.DeadlineBefore)
+	}
+	if actualEntity.Deadline.Month() != time.December || actualEntity.Deadline.Day() != 20 {
+		t.Errorf("Unmatch entitiy's deadline. deadline='%v'", actualEntity.Deadline)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"regexp"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/appengine/datastore"
+	"google.golang.org/appengine/log"
+)
+
+// 土日祝日のリマインド方針
+const (
+	holidayPolicySend  = "send"  // 土日祝日もリマインドする（デフォルト）
+	holidayPolicySkip  = "skip"  // 土日祝日はリマインドしない
+	holidayPolicyShift = "shift" // 土日祝日のリマインドを、直前の平日に前倒しする
+)
+
+/**
+ * `set holiday`コマンドであれば、指定された土日祝日のリマインド方針を返す
+ */
+func isSetHolidayCommand(command string) (bool, string) {
+	pattern := regexp.MustCompile(`^[ \n]*set holiday (send|skip|shift)[ \n]*$`)
+	matches := pattern.FindStringSubmatch(command)
+	if len(matches) == 2 {
+		return true, matches[1]
+	}
+	return false, ""
+}
+
+/**
+ * 購読者エンティティに、土日祝日のリマインド方針を書き込む
+ */
+func writeHolidayPolicy(c context.Context, mid string, policy string) error {
+	var entity subscriber
+
+	key := datastore.NewKey(c, "Subscriber", mid, 0, nil)
+	if err := datastore.Get(c, key, &entity); err != nil {
+		log.Errorf(c, "Error occurred at get Subscriber entity. mid:%v err:%v", mid, err)
+		return err
+	}
+
+	entity.HolidayPolicy = policy
+	if _, err := datastore.Put(c, key, &entity); err != nil {
+		log.Errorf(c, "Error occurred at put Subscriber entity. mid:%v err:%v", mid, err)
+		return err
+	}
+	return nil
+}
+
+/**
+ * 土日祝日のリマインド方針に従って、todayにリマインドすべき日（本来のリマインド日）を返す
+ *
+ * - send: todayのみ
+ * - skip: todayが平日であればtodayのみ、土日祝日であればなし
+ * - shift: todayが平日であれば、todayと、その翌日から続く土日祝日。土日祝日であればなし（前倒し済み）
+ */
+func (s *subscriber) reminderDays(today time.Time) []time.Time {
+	switch s.HolidayPolicy {
+	case holidayPolicySkip:
+		if !isBusinessDay(today) {
+			return []time.Time{}
+		}
+	case holidayPolicyShift:
+		if !isBusinessDay(today) {
+			return []time.Time{}
+		}
+		days := []time.Time{today}
+		for next := today.AddDate(0, 0, 1); !isBusinessDay(next); next = next.AddDate(0, 0, 1) {
+			days = append(days, next)
+		}
+		return days
+	}
+	return []time.Time{today}
+}
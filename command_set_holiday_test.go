@@ -0,0 +1,148 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"google.golang.org/appengine"
+	"google.golang.org/appengine/aetest"
+	"google.golang.org/appengine/datastore"
+)
+
+/**
+ * `set holiday`コマンド判定と方針の取り出し
+ */
+func TestIsSetHolidayCommand(t *testing.T) {
+	type testParameter struct {
+		text           string
+		expectedIs     bool
+		expectedPolicy string
+	}
+	testCases := []testParameter{{
+		text:           "set holiday skip",
+		expectedIs:     true,
+		expectedPolicy: "skip",
+	}, {
+		text:           "  set holiday shift\n\n", // 前後にノイズがあってもtrue
+		expectedIs:     true,
+		expectedPolicy: "shift",
+	}, {
+		text:           "set holiday later", // 未定義の方針
+		expectedIs:     false,
+		expectedPolicy: "",
+	}}
+
+	for _, current := range testCases {
+		actualIs, actualPolicy := isSetHolidayCommand(current.text)
+		if actualIs != current.expectedIs {
+			t.Errorf("Illegal return value. text:%v, returnd:%v", current.text, actualIs)
+		}
+		if actualPolicy != current.expectedPolicy {
+			t.Errorf("Illegal return value. text:%v, returnd:%v", current.text, actualPolicy)
+		}
+	}
+}
+
+/**
+ * 土日祝日の方針によるリマインド日の算出
+ */
+func TestReminderDays(t *testing.T) {
+	type testParameter struct {
+		policy   string
+		today    time.Time
+		expected []string
+	}
+	tz, _ := time.LoadLocation("Asia/Tokyo")
+	testCases := []testParameter{{
+		policy:   holidayPolicySend, // 土曜日でもリマインド
+		today:    time.Date(2016, time.December, 3, 8, 0, 0, 0, tz),
+		expected: []string{"2016-12-03"},
+	}, {
+		policy:   "", // 未設定はsendと同じ
+		today:    time.Date(2016, time.December, 3, 8, 0, 0, 0, tz),
+		expected: []string{"2016-12-03"},
+	}, {
+		policy:   holidayPolicySkip, // 土曜日はリマインドしない
+		today:    time.Date(2016, time.December, 3, 8, 0, 0, 0, tz),
+		expected: []string{},
+	}, {
+		policy:   holidayPolicySkip, // 平日はリマインド
+		today:    time.Date(2016, time.December, 2, 8, 0, 0, 0, tz),
+		expected: []string{"2016-12-02"},
+	}, {
+		policy:   holidayPolicyShift, // 金曜日に、土日の分を前倒し
+		today:    time.Date(2016, time.December, 2, 8, 0, 0, 0, tz),
+		expected: []string{"2016-12-02", "2016-12-03", "2016-12-04"},
+	}, {
+		policy:   holidayPolicyShift, // 前倒し済みなので、日曜日はリマインドしない
+		today:    time.Date(2016, time.December, 4, 8, 0, 0, 0, tz),
+		expected: []string{},
+	}, {
+		policy:   holidayPolicyShift, // 祝日（水曜日）の前日
+		today:    time.Date(2016, time.November, 22, 8, 0, 0, 0, tz),
+		expected: []string{"2016-11-22", "2016-11-23"},
+	}}
+
+	for _, current := range testCases {
+		s := subscriber{HolidayPolicy: current.policy}
+		actual := s.reminderDays(current.today)
+		actualStrings := []string{}
+		for _, day := range actual {
+			actualStrings = append(actualStrings, day.Format("2006-01-02"))
+		}
+		if len(actualStrings) != len(current.expected) {
+			t.Errorf("Illegal reminder days. policy:%v, today:%v, returnd:%v", current.policy, current.today, actualStrings)
+			continue
+		}
+		for i := range actualStrings {
+			if actualStrings[i] != current.expected[i] {
+				t.Errorf("Illegal reminder days. policy:%v, today:%v, returnd:%v", current.policy, current.today, actualStrings)
+				break
+			}
+		}
+	}
+}
+
+/**
+ * データストアに土日祝日のリマインド方針を書き込む関数のテスト（正常系）
+ */
+func TestWriteHolidayPolicyNormally(t *testing.T) {
+	opt := aetest.Options{StronglyConsistentDatastore: true} //データストアに即反映
+	instance, err := aetest.NewInstance(&opt)
+	if err != nil {
+		t.Fatalf("Failed to create aetest instance: %v", err)
+	}
+	defer instance.Close()
+
+	// Contextが必要なので、ダミーのhttp.Request
+	req, err := instance.NewRequest("POST", "/task/analyzecommand", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := appengine.NewContext(req)
+
+	mid := "C00000000000000000000000000000000"
+
+	// 更新される購読者エンティティを用意しておく
+	entity := subscriber{
+		MID: mid,
+	}
+	key := datastore.NewKey(c, "Subscriber", mid, 0, nil)
+	if _, err = datastore.Put(c, key, &entity); err != nil {
+		t.Fatal(err)
+	}
+
+	// execute
+	if err := writeHolidayPolicy(c, mid, holidayPolicyShift); err != nil {
+		t.Fatal(err)
+	}
+
+	// データストアに方針が書き込まれていること
+	var actualEntity subscriber
+	if err = datastore.Get(c, key, &actualEntity); err != nil {
+		t.Fatal(err)
+	}
+	if actualEntity.HolidayPolicy != holidayPolicyShift {
+		t.Errorf("Unmatch entitiy's holiday policy. policy='%v'", actualEntity.HolidayPolicy)
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"regexp"
+
+	"golang.org/x/net/context"
+	"google.golang.org/appengine/datastore"
+	"google.golang.org/appengine/log"
+)
+
+/**
+ * `set lang`コマンドであれば、指定された言語を返す
+ */
+func isSetLangCommand(command string) (bool, string) {
+	pattern := regexp.MustCompile(`^[ \n]*set lang(?:uage)? (ja|en)[ \n]*$`)
+	matches := pattern.FindStringSubmatch(command)
+	if len(matches) == 2 {
+		return true, matches[1]
+	}
+	return false, ""
+}
+
+/**
+ * 購読者エンティティに、言語を書き込む
+ */
+func writeLang(c context.Context, mid string, lang string) error {
+	var entity subscriber
+
+	key := datastore.NewKey(c, "Subscriber", mid, 0, nil)
+	if err := datastore.Get(c, key, &entity); err != nil {
+		log.Errorf(c, "Error occurred at get Subscriber entity. mid:%v err:%v", mid, err)
+		return err
+	}
+
+	entity.Lang = lang
+	if _, err := datastore.Put(c, key, &entity); err != nil {
+		log.Errorf(c, "Error occurred at put Subscriber entity. mid:%v err:%v", mid, err)
+		return err
+	}
+	return nil
+}
+
+/**
+ * 購読者の言語を返す。未設定、もしくは対応していない言語であればデフォルトの言語
+ */
+func (s *subscriber) lang() string {
+	if isSupportedLang(s.Lang) {
+		return s.Lang
+	}
+	return defaultLang
+}
+
+/**
+ * 購読者エンティティを読み込んで、言語を返す。読み込めなければデフォルトの言語
+ */
+func readLang(c context.Context, mid string) string {
+	var entity subscriber
+
+	key := datastore.NewKey(c, "Subscriber", mid, 0, nil)
+	if err := datastore.Get(c, key, &entity); err != nil {
+		return defaultLang
+	}
+	return entity.lang()
+}
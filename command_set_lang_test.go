@@ -0,0 +1,101 @@
+package main
+
+import (
+	"testing"
+
+	"google.golang.org/appengine"
+	"google.golang.org/appengine/aetest"
+	"google.golang.org/appengine/datastore"
+)
+
+/**
+ * `set lang`コマンド判定と言語の取り出し
+ */
+func TestIsSetLangCommand(t *testing.T) {
+	type testParameter struct {
+		text         string
+		expectedIs   bool
+		expectedLang string
+	}
+	testCases := []testParameter{{
+		text:         "set lang en",
+		expectedIs:   true,
+		expectedLang: "en",
+	}, {
+		text:         "  set language ja\n\n", // 前後にノイズ、languageと書いてもtrue
+		expectedIs:   true,
+		expectedLang: "ja",
+	}, {
+		text:         "set lang fr", // 対応していない言語
+		expectedIs:   false,
+		expectedLang: "",
+	}}
+
+	for _, current := range testCases {
+		actualIs, actualLang := isSetLangCommand(current.text)
+		if actualIs != current.expectedIs {
+			t.Errorf("Illegal return value. text:%v, returnd:%v", current.text, actualIs)
+		}
+		if actualLang != current.expectedLang {
+			t.Errorf("Illegal return value. text:%v, returnd:%v", current.text, actualLang)
+		}
+	}
+}
+
+/**
+ * 購読者の言語（未設定や未対応の言語はデフォルト）
+ */
+func TestSubscriberLang(t *testing.T) {
+	testCases := map[string]string{
+		"":   langJa,
+		"ja": langJa,
+		"en": langEn,
+		"fr": langJa,
+	}
+	for lang, expected := range testCases {
+		s := subscriber{Lang: lang}
+		if actual := s.lang(); actual != expected {
+			t.Errorf("Illegal return value. lang:%v, returnd:%v", lang, actual)
+		}
+	}
+}
+
+/**
+ * データストアに言語を書き込む関数のテスト（正常系）
+ */
+func TestWriteLangNormally(t *testing.T) {
+	opt := aetest.Options{StronglyConsistentDatastore: true} //データストアに即反映
+	instance, err := aetest.NewInstance(&opt)
+	if err != nil {
+		t.Fatalf("Failed to create aetest instance: %v", err)
+	}
+	defer instance.Close()
+
+	// Contextが必要なので、ダミーのhttp.Request
+	req, err := instance.NewRequest("POST", "/task/analyzecommand", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := appengine.NewContext(req)
+
+	mid := "C00000000000000000000000000000000"
+
+	// 更新される購読者エンティティを用意しておく
+	entity := subscriber{
+		MID: mid,
+	}
+	key := datastore.NewKey(c, "Subscriber", mid, 0, nil)
+	if _, err = datastore.Put(c, key, &entity); err != nil {
+		t.Fatal(err)
+	}
+
+	// execute
+	if err := writeLang(c, mid, langEn); err != nil {
+		t.Fatal(err)
+	}
+
+	// データストアに言語が書き込まれ、読み込めること
+	if actual := readLang(c, mid); actual != langEn {
+		t.Errorf("Unmatch entitiy's lang. lang='%v'", actual)
+	}
+}
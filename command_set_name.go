@@ -9,19 +9,28 @@ import (
 )
 
 /**
- * `set name`コマンドであれば、指定された名前部分を返す
+ * `set name`コマンドであれば、指定された名前部分を返す（名前全体を引用符で囲める）
+ *
+ * コマンドと名前の間の全角スペースも許容する
  */
 func isSetNameCommand(command string) (bool, string) {
-	pattern := regexp.MustCompile(`^[ \n]*set name (.+)[ \n]*$`)
+	pattern := regexp.MustCompile(`^[ \n　]*set[ 　]+name[ 　]+(.+?)[ \n　]*$`)
 	matches := pattern.FindStringSubmatch(command)
 	if len(matches) == 2 {
-		return true, matches[1]
+		if name := commandArg(matches[1]); name != "" {
+			return true, name
+		}
 	}
 	return false, ""
 }
 
+// 表示名の手動設定を解除して、LINEから取得した名前に戻す指定
+const nameAuto = "auto"
+
 /**
- * 購読者エンティティに、グループ名を書き込む
+ * 購読者エンティティに、グループ名を書き込む。表示名の定期的な更新で上書きしないよう、手動で設定したことも記録する
+ *
+ * `auto`を指定した場合は手動設定を解除する（表示名は、次の定期的な更新で取得した名前になる）
  */
 func writeName(c context.Context, mid string, name string) error {
 	var entity subscriber
@@ -32,7 +41,12 @@ func writeName(c context.Context, mid string, name string) error {
 		return err
 	}
 
-	entity.DisplayName = name
+	if name == nameAuto {
+		entity.NameOverridden = false
+	} else {
+		entity.DisplayName = name
+		entity.NameOverridden = true
+	}
 	if _, err := datastore.Put(c, key, &entity); err != nil {
 		log.Errorf(c, "Error occurred at put Subscriber entity. mid:%v err:%v", mid, err)
 		return err
@@ -25,6 +25,22 @@ func TestIsSetNameCommand(t *testing.T) {
 		text:         "     set name テストグループ\n\n", // 前後にノイズがあってもtrue
 		expectedIs:   true,
 		expectedName: "テストグループ",
+	}, {
+		text:         `set name "テスト グループ"`, // 空白を含む名前は引用符で囲む
+		expectedIs:   true,
+		expectedName: "テスト グループ",
+	}, {
+		text:         "set name テスト  グループ", // 引用符なしの名前は、空白をそのまま残す
+		expectedIs:   true,
+		expectedName: "テスト  グループ",
+	}, {
+		text:         "set　name　テスト　グループ", // 全角スペース
+		expectedIs:   true,
+		expectedName: "テスト　グループ",
+	}, {
+		text:         `set name ""`, // 空の名前
+		expectedIs:   false,
+		expectedName: "",
 	}, {
 		text:         "set your name コマンド間違ったグループ", // コマンド誤り
 		expectedIs:   false,
@@ -86,4 +102,19 @@ func TestWriteNameNormally(t *testing.T) {
 	if actualEntity.DisplayName != expectedName {
 		t.Errorf("Unmatch entitiy's DisplayName. DisplayName='%v'", actualEntity.DisplayName)
 	}
+	// 表示名の定期的な更新で上書きされないこと
+	if !actualEntity.NameOverridden {
+		t.Errorf("NameOverridden was not set")
+	}
+
+	// `auto`を指定すると、表示名はそのままで手動設定が解除されること
+	if err := writeName(c, mid, nameAuto); err != nil {
+		t.Fatal(err)
+	}
+	if err = datastore.Get(c, key, &actualEntity); err != nil {
+		t.Fatal(err)
+	}
+	if actualEntity.DisplayName != expectedName || actualEntity.NameOverridden {
+		t.Errorf("Unmatch entitiy. DisplayName='%v' NameOverridden=%v", actualEntity.DisplayName, actualEntity.NameOverridden)
+	}
 }
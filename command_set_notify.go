@@ -0,0 +1,41 @@
+package main
+
+import (
+	"regexp"
+
+	"golang.org/x/net/context"
+	"google.golang.org/appengine/datastore"
+	"google.golang.org/appengine/log"
+)
+
+/**
+ * `set notify changes`コマンドであれば、出欠の変更を通知するか（on/off）を返す
+ */
+func isSetNotifyChangesCommand(command string) (bool, bool) {
+	pattern := regexp.MustCompile(`^[ \n]*set notify changes (on|off)[ \n]*$`)
+	matches := pattern.FindStringSubmatch(command)
+	if len(matches) == 2 {
+		return true, matches[1] == "on"
+	}
+	return false, false
+}
+
+/**
+ * 購読者エンティティに、出欠の変更を通知するかを書き込む
+ */
+func writeNotifyChanges(c context.Context, mid string, notify bool) error {
+	var entity subscriber
+
+	key := datastore.NewKey(c, "Subscriber", mid, 0, nil)
+	if err := datastore.Get(c, key, &entity); err != nil {
+		log.Errorf(c, "Error occurred at get Subscriber entity. mid:%v err:%v", mid, err)
+		return err
+	}
+
+	entity.NotifyChanges = notify
+	if _, err := datastore.Put(c, key, &entity); err != nil {
+		log.Errorf(c, "Error occurred at put Subscriber entity. mid:%v err:%v", mid, err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"testing"
+
+	"google.golang.org/appengine"
+	"google.golang.org/appengine/aetest"
+	"google.golang.org/appengine/datastore"
+)
+
+/**
+ * `set notify changes`コマンド判定とon/offの取り出し
+ */
+func TestIsSetNotifyChangesCommand(t *testing.T) {
+	type testParameter struct {
+		text           string
+		expectedIs     bool
+		expectedNotify bool
+	}
+	testCases := []testParameter{{
+		text:           "set notify changes on",
+		expectedIs:     true,
+		expectedNotify: true,
+	}, {
+		text:           "  set notify changes off\n\n", // 前後にノイズがあってもtrue
+		expectedIs:     true,
+		expectedNotify: false,
+	}, {
+		text:           "set notify changes yes", // 値の誤り
+		expectedIs:     false,
+		expectedNotify: false,
+	}}
+
+	for _, current := range testCases {
+		actualIs, actualNotify := isSetNotifyChangesCommand(current.text)
+		if actualIs != current.expectedIs {
+			t.Errorf("Illegal return value. text:%v, returnd:%v", current.text, actualIs)
+		}
+		if actualNotify != current.expectedNotify {
+			t.Errorf("Illegal return value. text:%v, returnd:%v", current.text, actualNotify)
+		}
+	}
+}
+
+/**
+ * データストアに出欠変更の通知設定を書き込む関数のテスト（正常系）
+ */
+func TestWriteNotifyChangesNormally(t *testing.T) {
+	opt := aetest.Options{StronglyConsistentDatastore: true} //データストアに即反映
+	instance, err := aetest.NewInstance(&opt)
+	if err != nil {
+		t.Fatalf("Failed to create aetest instance: %v", err)
+	}
+	defer instance.Close()
+
+	// Contextが必要なので、ダミーのhttp.Request
+	req, err := instance.NewRequest("POST", "/task/analyzecommand", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := appengine.NewContext(req)
+
+	mid := "C00000000000000000000000000000000"
+
+	// 更新される購読者エンティティを用意しておく
+	entity := subscriber{
+		MID: mid,
+	}
+	key := datastore.NewKey(c, "Subscriber", mid, 0, nil)
+	if _, err = datastore.Put(c, key, &entity); err != nil {
+		t.Fatal(err)
+	}
+
+	// execute
+	if err := writeNotifyChanges(c, mid, true); err != nil {
+		t.Fatal(err)
+	}
+
+	// データストアに設定が書き込まれていること
+	var actualEntity subscriber
+	if err = datastore.Get(c, key, &actualEntity); err != nil {
+		t.Fatal(err)
+	}
+	if !actualEntity.NotifyChanges {
+		t.Errorf("Unmatch entitiy's notify changes. notify='%v'", actualEntity.NotifyChanges)
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"regexp"
+	"strconv"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/appengine/datastore"
+	"google.golang.org/appengine/log"
+)
+
+/**
+ * `set quiet`コマンドであれば、リマインドを送信しない時間帯の開始・終了時刻を返す
+ *
+ * `set quiet off`の場合は、開始・終了とも0を返す（時間帯なし）
+ */
+func isSetQuietCommand(command string) (bool, int, int) {
+	if regexp.MustCompile(`^[ \n]*set quiet off[ \n]*$`).MatchString(command) {
+		return true, 0, 0
+	}
+
+	pattern := regexp.MustCompile(`^[ \n]*set quiet (\d{1,2})-(\d{1,2})[ \n]*$`)
+	matches := pattern.FindStringSubmatch(command)
+	if len(matches) != 3 {
+		return false, 0, 0
+	}
+	start, _ := strconv.Atoi(matches[1])
+	end, _ := strconv.Atoi(matches[2])
+	if start > 23 || end > 23 {
+		return false, 0, 0
+	}
+	return true, start, end
+}
+
+/**
+ * 購読者エンティティに、リマインドを送信しない時間帯を書き込む
+ */
+func writeQuietHours(c context.Context, mid string, start int, end int) error {
+	var entity subscriber
+
+	key := datastore.NewKey(c, "Subscriber", mid, 0, nil)
+	if err := datastore.Get(c, key, &entity); err != nil {
+		log.Errorf(c, "Error occurred at get Subscriber entity. mid:%v err:%v", mid, err)
+		return err
+	}
+
+	entity.QuietStart = start
+	entity.QuietEnd = end
+	if _, err := datastore.Put(c, key, &entity); err != nil {
+		log.Errorf(c, "Error occurred at put Subscriber entity. mid:%v err:%v", mid, err)
+		return err
+	}
+	return nil
+}
+
+/**
+ * 指定時刻が、リマインドを送信しない時間帯に含まれていればtrueを返す
+ *
+ * 開始時刻が終了時刻より遅い場合（22-7など）は、日付をまたぐ時間帯として扱う
+ */
+func (s *subscriber) isQuietHours(t time.Time) bool {
+	if s.QuietStart == s.QuietEnd {
+		return false
+	}
+	hour := t.Hour()
+	if s.QuietStart < s.QuietEnd {
+		return s.QuietStart <= hour && hour < s.QuietEnd
+	}
+	return hour >= s.QuietStart || hour < s.QuietEnd
+}
+
+/**
+ * 指定時刻以降で、リマインドを送信しない時間帯が終了する時刻を返す
+ */
+func (s *subscriber) quietHoursEnd(t time.Time) time.Time {
+	end := time.Date(t.Year(), t.Month(), t.Day(), s.QuietEnd, 0, 0, 0, t.Location())
+	if !end.After(t) {
+		end = end.AddDate(0, 0, 1)
+	}
+	return end
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"google.golang.org/appengine"
+	"google.golang.org/appengine/aetest"
+	"google.golang.org/appengine/datastore"
+)
+
+/**
+ * `set quiet`コマンド判定と時間帯の取り出し
+ */
+func TestIsSetQuietCommand(t *testing.T) {
+	type testParameter struct {
+		text          string
+		expectedIs    bool
+		expectedStart int
+		expectedEnd   int
+	}
+	testCases := []testParameter{{
+		text:          "set quiet 22-7",
+		expectedIs:    true,
+		expectedStart: 22,
+		expectedEnd:   7,
+	}, {
+		text:          "   set quiet 0-6\n\n", // 前後にノイズがあってもtrue
+		expectedIs:    true,
+		expectedStart: 0,
+		expectedEnd:   6,
+	}, {
+		text:          "set quiet off", // 解除
+		expectedIs:    true,
+		expectedStart: 0,
+		expectedEnd:   0,
+	}, {
+		text:          "set quiet 22-24", // 範囲外の時刻
+		expectedIs:    false,
+		expectedStart: 0,
+		expectedEnd:   0,
+	}, {
+		text:          "set quiet 22:00-7:00", // 書式誤り
+		expectedIs:    false,
+		expectedStart: 0,
+		expectedEnd:   0,
+	}}
+
+	for _, current := range testCases {
+		actualIs, actualStart, actualEnd := isSetQuietCommand(current.text)
+		if actualIs != current.expectedIs {
+			t.Errorf("Illegal return value. text:%v, returnd:%v", current.text, actualIs)
+		}
+		if actualStart != current.expectedStart || actualEnd != current.expectedEnd {
+			t.Errorf("Illegal return value. text:%v, returnd:%v-%v", current.text, actualStart, actualEnd)
+		}
+	}
+}
+
+/**
+ * リマインドを送信しない時間帯の判定と、終了時刻の算出
+ */
+func TestQuietHours(t *testing.T) {
+	type testParameter struct {
+		start       int
+		end         int
+		now         time.Time
+		expectedIs  bool
+		expectedEnd time.Time
+	}
+	tz, _ := time.LoadLocation("Asia/Tokyo")
+	testCases := []testParameter{{
+		start:       22, // 日付をまたぐ時間帯（開始後）
+		end:         7,
+		now:         time.Date(2016, time.December, 1, 23, 0, 0, 0, tz),
+		expectedIs:  true,
+		expectedEnd: time.Date(2016, time.December, 2, 7, 0, 0, 0, tz),
+	}, {
+		start:       22, // 日付をまたぐ時間帯（日付変更後）
+		end:         7,
+		now:         time.Date(2016, time.December, 1, 3, 0, 0, 0, tz),
+		expectedIs:  true,
+		expectedEnd: time.Date(2016, time.December, 1, 7, 0, 0, 0, tz),
+	}, {
+		start:      22, // 時間帯の外
+		end:        7,
+		now:        time.Date(2016, time.December, 1, 8, 0, 0, 0, tz),
+		expectedIs: false,
+	}, {
+		start:       6, // 日付をまたがない時間帯
+		end:         9,
+		now:         time.Date(2016, time.December, 1, 8, 0, 0, 0, tz),
+		expectedIs:  true,
+		expectedEnd: time.Date(2016, time.December, 1, 9, 0, 0, 0, tz),
+	}, {
+		start:      0, // 時間帯なし
+		end:        0,
+		now:        time.Date(2016, time.December, 1, 8, 0, 0, 0, tz),
+		expectedIs: false,
+	}}
+
+	for _, current := range testCases {
+		s := subscriber{QuietStart: current.start, QuietEnd: current.end}
+		actualIs := s.isQuietHours(current.now)
+		if actualIs != current.expectedIs {
+			t.Errorf("Illegal return value. quiet:%v-%v, now:%v, returnd:%v", current.start, current.end, current.now, actualIs)
+		}
+		if actualIs {
+			actualEnd := s.quietHoursEnd(current.now)
+			if !actualEnd.Equal(current.expectedEnd) {
+				t.Errorf("Illegal end of quiet hours. quiet:%v-%v, now:%v, returnd:%v", current.start, current.end, current.now, actualEnd)
+			}
+		}
+	}
+}
+
+/**
+ * データストアにリマインドを送信しない時間帯を書き込む関数のテスト（正常系）
+ */
+func TestWriteQuietHoursNormally(t *testing.T) {
+	opt := aetest.Options{StronglyConsistentDatastore: true} //データストアに即反映
+	instance, err := aetest.NewInstance(&opt)
+	if err != nil {
+		t.Fatalf("Failed to create aetest instance: %v", err)
+	}
+	defer instance.Close()
+
+	// Contextが必要なので、ダミーのhttp.Request
+	req, err := instance.NewRequest("POST", "/task/analyzecommand", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := appengine.NewContext(req)
+
+	mid := "C00000000000000000000000000000000"
+
+	// 更新される購読者エンティティを用意しておく
+	entity := subscriber{
+		MID: mid,
+	}
+	key := datastore.NewKey(c, "Subscriber", mid, 0, nil)
+	if _, err = datastore.Put(c, key, &entity); err != nil {
+		t.Fatal(err)
+	}
+
+	// execute
+	if err := writeQuietHours(c, mid, 22, 7); err != nil {
+		t.Fatal(err)
+	}
+
+	// データストアに時間帯が書き込まれていること
+	var actualEntity subscriber
+	if err = datastore.Get(c, key, &actualEntity); err != nil {
+		t.Fatal(err)
+	}
+	if actualEntity.QuietStart != 22 || actualEntity.QuietEnd != 7 {
+		t.Errorf("Unmatch entitiy's quiet hours. quiet='%v-%v'", actualEntity.QuietStart, actualEntity.QuietEnd)
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"regexp"
+	"strconv"
+
+	"golang.org/x/net/context"
+	"google.golang.org/appengine/datastore"
+	"google.golang.org/appengine/log"
+)
+
+// クイックリプライで選択肢として表示するリマインド時刻（クイックリプライは最大13項目）
+var remindTimeChoices = []int{6, 7, 8, 9, 10, 12, 15, 18, 19, 20, 21, 22}
+
+/**
+ * `set remindtime`コマンドであれば、指定された時刻（時）を返す。時刻の指定がなければ-1を返す
+ */
+func isSetRemindTimeCommand(command string) (bool, int) {
+	pattern := regexp.MustCompile(`^[ \n]*set remind ?time(?: ([0-9]{1,2})(?::00)?)?[ \n]*$`)
+	matches := pattern.FindStringSubmatch(command)
+	if len(matches) != 2 {
+		return false, 0
+	}
+	if matches[1] == "" {
+		return true, -1
+	}
+	hour, _ := strconv.Atoi(matches[1])
+	if hour > 23 {
+		return false, 0
+	}
+	return true, hour
+}
+
+/**
+ * 購読者エンティティに、リマインド時刻（時）を書き込む
+ */
+func writeRemindTime(c context.Context, mid string, hour int) error {
+	var entity subscriber
+
+	key := datastore.NewKey(c, "Subscriber", mid, 0, nil)
+	if err := datastore.Get(c, key, &entity); err != nil {
+		log.Errorf(c, "Error occurred at get Subscriber entity. mid:%v err:%v", mid, err)
+		return err
+	}
+
+	entity.RemindTime = hour
+	if _, err := datastore.Put(c, key, &entity); err != nil {
+		log.Errorf(c, "Error occurred at put Subscriber entity. mid:%v err:%v", mid, err)
+		return err
+	}
+	return nil
+}
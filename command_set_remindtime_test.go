@@ -0,0 +1,96 @@
+package main
+
+import (
+	"testing"
+
+	"google.golang.org/appengine"
+	"google.golang.org/appengine/aetest"
+	"google.golang.org/appengine/datastore"
+)
+
+/**
+ * `set remindtime`コマンド判定と時刻の取り出し
+ */
+func TestIsSetRemindTimeCommand(t *testing.T) {
+	type testParameter struct {
+		text         string
+		expectedIs   bool
+		expectedHour int
+	}
+	testCases := []testParameter{{
+		text:         "set remindtime 19",
+		expectedIs:   true,
+		expectedHour: 19,
+	}, {
+		text:         "  set remind time 7:00\n\n", // 前後にノイズ、分の指定があってもtrue
+		expectedIs:   true,
+		expectedHour: 7,
+	}, {
+		text:         "set remindtime", // 時刻の指定なしは選択肢を表示する
+		expectedIs:   true,
+		expectedHour: -1,
+	}, {
+		text:         "set remindtime 24", // 範囲外
+		expectedIs:   false,
+		expectedHour: 0,
+	}, {
+		text:         "set remindtime 7:30", // 分は指定できない
+		expectedIs:   false,
+		expectedHour: 0,
+	}}
+
+	for _, current := range testCases {
+		actualIs, actualHour := isSetRemindTimeCommand(current.text)
+		if actualIs != current.expectedIs {
+			t.Errorf("Illegal return value. text:%v, returnd:%v", current.text, actualIs)
+		}
+		if actualHour != current.expectedHour {
+			t.Errorf("Illegal return value. text:%v, returnd:%v", current.text, actualHour)
+		}
+	}
+}
+
+/**
+ * データストアにリマインド時刻を書き込む関数のテスト（正常系）
+ */
+func TestWriteRemindTimeNormally(t *testing.T) {
+	opt := aetest.Options{StronglyConsistentDatastore: true} //データストアに即反映
+	instance, err := aetest.NewInstance(&opt)
+	if err != nil {
+		t.Fatalf("Failed to create aetest instance: %v", err)
+	}
+	defer instance.Close()
+
+	// Contextが必要なので、ダミーのhttp.Request
+	req, err := instance.NewRequest("POST", "/task/analyzecommand", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := appengine.NewContext(req)
+
+	mid := "C00000000000000000000000000000000"
+
+	// 更新される購読者エンティティを用意しておく
+	entity := subscriber{
+		MID:        mid,
+		RemindTime: 8,
+	}
+	key := datastore.NewKey(c, "Subscriber", mid, 0, nil)
+	if _, err = datastore.Put(c, key, &entity); err != nil {
+		t.Fatal(err)
+	}
+
+	// execute
+	if err := writeRemindTime(c, mid, 19); err != nil {
+		t.Fatal(err)
+	}
+
+	// データストアに設定が書き込まれていること
+	var actualEntity subscriber
+	if err = datastore.Get(c, key, &actualEntity); err != nil {
+		t.Fatal(err)
+	}
+	if actualEntity.RemindTime != 19 {
+		t.Errorf("Unmatch entitiy's remind time. time='%v'", actualEntity.RemindTime)
+	}
+}
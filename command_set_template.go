@@ -0,0 +1,77 @@
+package main
+
+import (
+	"regexp"
+
+	"golang.org/x/net/context"
+	"google.golang.org/appengine/datastore"
+	"google.golang.org/appengine/log"
+)
+
+/**
+ * `set template`コマンドであれば、指定されたテンプレートを返す。`default`の指定はデフォルトに戻す（空文字を返す）
+ */
+func isSetTemplateCommand(command string) (bool, string) {
+	pattern := regexp.MustCompile(`^[ \n]*set template[ \n]+((?s:.+?))[ \n]*$`)
+	matches := pattern.FindStringSubmatch(command)
+	if len(matches) != 2 {
+		return false, ""
+	}
+	if matches[1] == "default" {
+		return true, ""
+	}
+	return true, matches[1]
+}
+
+/**
+ * テンプレートを検証、適用できなかったエラーを、返信の言語の文言にして返す（テンプレートの構文エラーはそのまま）
+ */
+func templateErrorMessage(lang string, err error) string {
+	keys := map[error]string{
+		errTemplateTooLong:       "template.too_long",
+		errTemplateCall:          "template.call",
+		errTemplateDefine:        "template.define",
+		errTemplateTooDeep:       "template.too_deep",
+		errTemplateOutputTooLong: "template.output_too_long",
+		errTemplateOutputEmpty:   "template.output_empty",
+	}
+	if key, exist := keys[err]; exist {
+		return msg(lang, key)
+	}
+	return err.Error()
+}
+
+/**
+ * `preview template`コマンドであればtrueを返す
+ */
+func isPreviewTemplateCommand(command string) bool {
+	pattern := regexp.MustCompile(`^[ \n]*preview template[ \n]*$`)
+	return pattern.MatchString(command)
+}
+
+/**
+ * 購読者エンティティに、リマインドのテンプレートを書き込む。サンプルの日程に適用できなければエラー
+ */
+func writeReminderTemplate(c context.Context, mid string, text string) error {
+	if len(text) > 0 {
+		sample := sampleSchedule()
+		if _, err := renderReminderTemplate(text, sample.reminderTemplateData("")); err != nil {
+			return err
+		}
+	}
+
+	var entity subscriber
+
+	key := datastore.NewKey(c, "Subscriber", mid, 0, nil)
+	if err := datastore.Get(c, key, &entity); err != nil {
+		log.Errorf(c, "Error occurred at get Subscriber entity. mid:%v err:%v", mid, err)
+		return err
+	}
+
+	entity.ReminderTemplate = text
+	if _, err := datastore.Put(c, key, &entity); err != nil {
+		log.Errorf(c, "Error occurred at put Subscriber entity. mid:%v err:%v", mid, err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"testing"
+
+	"google.golang.org/appengine"
+	"google.golang.org/appengine/aetest"
+	"google.golang.org/appengine/datastore"
+)
+
+/**
+ * `set template`コマンド判定とテンプレートの取り出し
+ */
+func TestIsSetTemplateCommand(t *testing.T) {
+	type testParameter struct {
+		text             string
+		expectedIs       bool
+		expectedTemplate string
+	}
+	testCases := []testParameter{{
+		text:             "set template {{.Date}}の出欠\n○{{.Present}}名",
+		expectedIs:       true,
+		expectedTemplate: "{{.Date}}の出欠\n○{{.Present}}名",
+	}, {
+		text:             "set template\n{{.Date}}\n\n", // 改行で区切ってもよい
+		expectedIs:       true,
+		expectedTemplate: "{{.Date}}",
+	}, {
+		text:             "set template default", // デフォルトに戻す
+		expectedIs:       true,
+		expectedTemplate: "",
+	}, {
+		text:             "set template", // テンプレートの指定なし
+		expectedIs:       false,
+		expectedTemplate: "",
+	}}
+
+	for _, current := range testCases {
+		actualIs, actualTemplate := isSetTemplateCommand(current.text)
+		if actualIs != current.expectedIs {
+			t.Errorf("Illegal return value. text:%v, returnd:%v", current.text, actualIs)
+		}
+		if actualTemplate != current.expectedTemplate {
+			t.Errorf("Illegal return value. text:%v, returnd:%v", current.text, actualTemplate)
+		}
+	}
+}
+
+/**
+ * `preview template`コマンド判定
+ */
+func TestIsPreviewTemplateCommand(t *testing.T) {
+	type testParameter struct {
+		text     string
+		expected bool
+	}
+	testCases := []testParameter{{
+		text:     "preview template",
+		expected: true,
+	}, {
+		text:     "  preview template \n\n", // 前後にノイズがあってもtrue
+		expected: true,
+	}, {
+		text:     "preview", //コマンド誤り
+		expected: false,
+	}}
+
+	for _, current := range testCases {
+		actual := isPreviewTemplateCommand(current.text)
+		if actual != current.expected {
+			t.Errorf("Illegal return value. text:%v, returnd:%v", current.text, actual)
+		}
+	}
+}
+
+/**
+ * データストアにリマインドのテンプレートを書き込む関数のテスト（不正なテンプレートは書き込まない）
+ */
+func TestWriteReminderTemplate(t *testing.T) {
+	opt := aetest.Options{StronglyConsistentDatastore: true} //データストアに即反映
+	instance, err := aetest.NewInstance(&opt)
+	if err != nil {
+		t.Fatalf("Failed to create aetest instance: %v", err)
+	}
+	defer instance.Close()
+
+	// Contextが必要なので、ダミーのhttp.Request
+	req, err := instance.NewRequest("POST", "/task/analyzecommand", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := appengine.NewContext(req)
+
+	mid := "C00000000000000000000000000000000"
+
+	// 更新される購読者エンティティを用意しておく
+	entity := subscriber{
+		MID: mid,
+	}
+	key := datastore.NewKey(c, "Subscriber", mid, 0, nil)
+	if _, err = datastore.Put(c, key, &entity); err != nil {
+		t.Fatal(err)
+	}
+
+	// execute
+	if err := writeReminderTemplate(c, mid, "{{.Date}} ○{{.Present}}名"); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeReminderTemplate(c, mid, "{{.Unknown}}"); err == nil {
+		t.Errorf("Invalid template was accepted")
+	}
+
+	// データストアには正しいテンプレートのみ書き込まれていること
+	var actualEntity subscriber
+	if err = datastore.Get(c, key, &actualEntity); err != nil {
+		t.Fatal(err)
+	}
+	if actualEntity.ReminderTemplate != "{{.Date}} ○{{.Present}}名" {
+		t.Errorf("Unmatch entitiy's reminder template. template='%v'", actualEntity.ReminderTemplate)
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"regexp"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/appengine/datastore"
+	"google.golang.org/appengine/log"
+)
+
+// 購読者にタイムゾーンが設定されていない場合のデフォルト
+const defaultTimeZone = "Asia/Tokyo"
+
+/**
+ * `set timezone`コマンドであれば、指定されたタイムゾーン名を返す
+ */
+func isSetTimezoneCommand(command string) (bool, string) {
+	pattern := regexp.MustCompile(`^[ \n]*set timezone (\S+)[ \n]*$`)
+	matches := pattern.FindStringSubmatch(command)
+	if len(matches) == 2 {
+		return true, matches[1]
+	}
+	return false, ""
+}
+
+/**
+ * タイムゾーン名をtz databaseで検証して、Locationを返す
+ *
+ * time.LoadLocationは空文字を"UTC"、"Local"をサーバのローカル時刻として扱うため、これらは不正とする
+ */
+func loadTimeZone(name string) (*time.Location, error) {
+	if name == "" || name == "Local" {
+		return nil, errors.New("unknown time zone " + name)
+	}
+	return time.LoadLocation(name)
+}
+
+/**
+ * 購読者エンティティに、タイムゾーンを書き込む
+ */
+func writeTimeZone(c context.Context, mid string, name string) error {
+	var entity subscriber
+
+	if _, err := loadTimeZone(name); err != nil {
+		log.Warningf(c, "Invalid time zone. mid:%v timezone:%v err:%v", mid, name, err)
+		return err
+	}
+
+	key := datastore.NewKey(c, "Subscriber", mid, 0, nil)
+	if err := datastore.Get(c, key, &entity); err != nil {
+		log.Errorf(c, "Error occurred at get Subscriber entity. mid:%v err:%v", mid, err)
+		return err
+	}
+
+	entity.TimeZone = name
+	if _, err := datastore.Put(c, key, &entity); err != nil {
+		log.Errorf(c, "Error occurred at put Subscriber entity. mid:%v err:%v", mid, err)
+		return err
+	}
+	return nil
+}
+
+/**
+ * 購読者のタイムゾーンを返す。未設定もしくは不正な場合はAsia/Tokyo
+ */
+func (s *subscriber) location() *time.Location {
+	if loc, err := loadTimeZone(s.TimeZone); err == nil {
+		return loc
+	}
+	loc, _ := time.LoadLocation(defaultTimeZone)
+	return loc
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"testing"
+
+	"google.golang.org/appengine"
+	"google.golang.org/appengine/aetest"
+	"google.golang.org/appengine/datastore"
+)
+
+/**
+ * `set timezone`コマンド判定とタイムゾーン名の取り出し
+ */
+func TestIsSetTimezoneCommand(t *testing.T) {
+	type testParameter struct {
+		text         string
+		expectedIs   bool
+		expectedName string
+	}
+	testCases := []testParameter{{
+		text:         "set timezone Europe/Berlin",
+		expectedIs:   true,
+		expectedName: "Europe/Berlin",
+	}, {
+		text:         "  set timezone Asia/Tokyo\n\n", // 前後にノイズがあってもtrue
+		expectedIs:   true,
+		expectedName: "Asia/Tokyo",
+	}, {
+		text:         "set tz Europe/Berlin", // コマンド誤り
+		expectedIs:   false,
+		expectedName: "",
+	}}
+
+	for _, current := range testCases {
+		actualIs, actualName := isSetTimezoneCommand(current.text)
+		if actualIs != current.expectedIs {
+			t.Errorf("Illegal return value. text:%v, returnd:%v", current.text, actualIs)
+		}
+		if actualName != current.expectedName {
+			t.Errorf("Illegal return value. text:%v, returnd:%v", current.text, actualName)
+		}
+	}
+}
+
+/**
+ * 購読者のタイムゾーン（未設定、不正な値はAsia/Tokyo）
+ */
+func TestSubscriberLocation(t *testing.T) {
+	type testParameter struct {
+		timeZone string
+		expected string
+	}
+	testCases := []testParameter{{
+		timeZone: "Europe/Berlin",
+		expected: "Europe/Berlin",
+	}, {
+		timeZone: "", // 未設定（タイムゾーン対応前のエンティティ）
+		expected: "Asia/Tokyo",
+	}, {
+		timeZone: "Local", // サーバのローカル時刻は使わない
+		expected: "Asia/Tokyo",
+	}, {
+		timeZone: "Mars/Olympus_Mons", // tz databaseに存在しない
+		expected: "Asia/Tokyo",
+	}}
+
+	for _, current := range testCases {
+		s := subscriber{TimeZone: current.timeZone}
+		actual := s.location().String()
+		if actual != current.expected {
+			t.Errorf("Illegal location. timezone:%v, returnd:%v", current.timeZone, actual)
+		}
+	}
+}
+
+/**
+ * データストアにタイムゾーンを書き込む関数のテスト（正常系）
+ */
+func TestWriteTimeZoneNormally(t *testing.T) {
+	opt := aetest.Options{StronglyConsistentDatastore: true} //データストアに即反映
+	instance, err := aetest.NewInstance(&opt)
+	if err != nil {
+		t.Fatalf("Failed to create aetest instance: %v", err)
+	}
+	defer instance.Close()
+
+	// Contextが必要なので、ダミーのhttp.Request
+	req, err := instance.NewRequest("POST", "/task/analyzecommand", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := appengine.NewContext(req)
+
+	mid := "C00000000000000000000000000000000"
+	expectedTimeZone := "Europe/Berlin"
+
+	// 更新される購読者エンティティを用意しておく
+	entity := subscriber{
+		MID:      mid,
+		TimeZone: "Asia/Tokyo",
+	}
+	key := datastore.NewKey(c, "Subscriber", mid, 0, nil)
+	if _, err = datastore.Put(c, key, &entity); err != nil {
+		t.Fatal(err)
+	}
+
+	// execute
+	if err := writeTimeZone(c, mid, expectedTimeZone); err != nil {
+		t.Fatal(err)
+	}
+
+	// 不正なタイムゾーンはエラーとなり、書き込まれないこと
+	if err := writeTimeZone(c, mid, "Mars/Olympus_Mons"); err == nil {
+		t.Error("Invalid time zone was accepted")
+	}
+
+	// データストアにタイムゾーンが書き込まれていること
+	var actualEntity subscriber
+	if err = datastore.Get(c, key, &actualEntity); err != nil {
+		t.Fatal(err)
+	}
+	if actualEntity.TimeZone != expectedTimeZone {
+		t.Errorf("Unmatch entitiy's time zone. timezone='%v'", actualEntity.TimeZone)
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/line/line-bot-sdk-go/linebot"
+
+	"google.golang.org/appengine"
+	"google.golang.org/appengine/log"
+)
+
+/**
+ * `show schedule`コマンドであればtrueを返す
+ */
+func isShowScheduleCommand(command string) bool {
+	pattern := regexp.MustCompile(`^[ \n]*show schedules?[ \n]*$`)
+	return pattern.MatchString(command)
+}
+
+/**
+ * 当日以降の日程を、開催日順に返す
+ */
+func (m scheduleMap) futureSchedules(today time.Time) []schedule {
+	from := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, today.Location())
+
+	result := []schedule{}
+	for _, s := range m {
+		if !s.Date.Before(from) {
+			result = append(result, s)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Date.Before(result[j].Date) })
+	return result
+}
+
+/**
+ * 日程ごとの出欠の人数を一覧にしたメッセージを組み立てて返す
+ */
+func constructScheduleListMessage(lang string, schedules []schedule, hash string) string {
+	message := msg(lang, "schedule_list.title")
+	if len(schedules) == 0 {
+		message += "\n\n" + msg(lang, "schedule_list.none")
+	}
+	for i, s := range schedules {
+		if i == 0 {
+			message += "\n"
+		}
+		message += "\n" + msg(lang, "schedule_list.row", s.DateString, s.Present, s.Absent, s.Unknown)
+	}
+	return message + msg(lang, "summary.footer", "https://chouseisan.com/s?h="+hash)
+}
+
+/**
+ * 当日以降の日程ごとの出欠の人数を、グラフの画像を添えてリプライする
+ */
+func showSchedule(c context.Context, client *http.Client, mid string, token string, lang string) {
+	entity, err := readSubscriber(c, mid)
+	if err != nil {
+		replyMessage(c, client, token, msg(lang, "show_schedule.failed")+"\n"+err.Error())
+		return
+	}
+	if entity.ChouseisanHash == "" {
+		replyMessage(c, client, token, msg(lang, "command.no_event"))
+		return
+	}
+
+	today := time.Now().In(entity.location())
+	m := fetchScheduleMap(c, client, entity, today)
+	if m == nil {
+		replyMessage(c, client, token, msg(lang, "command.fetch_failed"))
+		return
+	}
+	schedules := m.futureSchedules(today)
+	messages := newTextMessages(constructScheduleListMessage(lang, schedules, entity.ChouseisanHash))
+	if len(schedules) > 0 {
+		// 出欠の傾向が分かるよう、グラフの画像を添える
+		chartURL := signChartURL(appengine.DefaultVersionHostname(c), mid, time.Now().Add(chartURLExpiresIn))
+		messages = append(messages, linebot.NewImageMessage(chartURL, chartURL))
+	}
+
+	bot, err := createBotClient(c, client)
+	if err != nil {
+		return
+	}
+	if err = sendReply(bot, token, messages); err != nil {
+		log.Errorf(c, "Error occurred at reply-message for show schedule. mid:%v, err: %v", mid, err)
+	}
+}
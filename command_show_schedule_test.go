@@ -0,0 +1,57 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+/**
+ * `show schedule`コマンド判定
+ */
+func TestIsShowScheduleCommand(t *testing.T) {
+	type testParameter struct {
+		text     string
+		expected bool
+	}
+	testCases := []testParameter{{
+		text:     "show schedule",
+		expected: true,
+	}, {
+		text:     "  show schedules \n\n", // 前後にノイズ、複数形でもtrue
+		expected: true,
+	}, {
+		text:     "schedule", //コマンド誤り
+		expected: false,
+	}}
+
+	for _, current := range testCases {
+		actual := isShowScheduleCommand(current.text)
+		if actual != current.expected {
+			t.Errorf("Illegal return value. text:%v, returnd:%v", current.text, actual)
+		}
+	}
+}
+
+/**
+ * 日程一覧のメッセージの組み立て（当日以降の日程を開催日順に）
+ */
+func TestConstructScheduleListMessage(t *testing.T) {
+	tz, _ := time.LoadLocation("Asia/Tokyo")
+	m := scheduleMap{}
+	for _, s := range []schedule{
+		{Date: time.Date(2016, time.December, 19, 0, 0, 0, 0, tz), DateString: "12/19(月)", Present: 1},
+		{Date: time.Date(2016, time.December, 24, 0, 0, 0, 0, tz), DateString: "12/24(土)", Present: 4, Absent: 1, Unknown: 2},
+		{Date: time.Date(2016, time.December, 20, 0, 0, 0, 0, tz), DateString: "12/20(火)", Present: 2, Absent: 0, Unknown: 5},
+	} {
+		m[s.Date.String()] = s
+	}
+
+	expected := "日程一覧\n\n" +
+		"12/20(火) ○2 ×0 不明/未入力5\n" +
+		"12/24(土) ○4 ×1 不明/未入力2\n\n" +
+		"詳細および出欠変更は「調整さん」へ\nhttps://chouseisan.com/s?h=3f7ffd73ba174332ae05bd363eba8e71"
+	actual := constructScheduleListMessage(langJa, m.futureSchedules(time.Date(2016, time.December, 20, 8, 0, 0, 0, tz)), "3f7ffd73ba174332ae05bd363eba8e71")
+	if actual != expected {
+		t.Errorf("Unmatch schedule list message\nexpect:\n%v\nactual:\n%v", expected, actual)
+	}
+}
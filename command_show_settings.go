@@ -0,0 +1,71 @@
+package main
+
+import (
+	"regexp"
+	"strconv"
+
+	"golang.org/x/net/context"
+	"google.golang.org/appengine/datastore"
+	"google.golang.org/appengine/log"
+)
+
+/**
+ * `show settings`コマンドであればtrueを返す
+ */
+func isShowSettingsCommand(command string) bool {
+	pattern := regexp.MustCompile(`^[ \n]*show settings?[ \n]*$`)
+	return pattern.MatchString(command)
+}
+
+/**
+ * 購読者エンティティを読み込んで返す
+ */
+func readSubscriber(c context.Context, mid string) (*subscriber, error) {
+	var entity subscriber
+
+	key := datastore.NewKey(c, "Subscriber", mid, 0, nil)
+	if err := datastore.Get(c, key, &entity); err != nil {
+		log.Errorf(c, "Error occurred at get Subscriber entity. mid:%v err:%v", mid, err)
+		return nil, err
+	}
+	return &entity, nil
+}
+
+/**
+ * 現在の設定を表示するメッセージを、購読者の言語で組み立てて返す
+ */
+func (s *subscriber) constructSettingsMessage() string {
+	lang := s.lang()
+
+	event := msg(lang, "settings.event_none")
+	if len(s.ChouseisanHash) > 0 {
+		event = "https://chouseisan.com/s?h=" + s.ChouseisanHash
+	}
+
+	timeZone := s.TimeZone
+	if timeZone == "" {
+		timeZone = defaultTimeZone
+	}
+
+	quiet := msg(lang, "settings.none")
+	if s.QuietStart != s.QuietEnd {
+		quiet = strconv.Itoa(s.QuietStart) + ":00〜" + strconv.Itoa(s.QuietEnd) + ":00"
+	}
+
+	holiday := msg(lang, "settings.holiday_send")
+	if s.HolidayPolicy == holidayPolicySkip || s.HolidayPolicy == holidayPolicyShift {
+		holiday = msg(lang, "settings.holiday_"+s.HolidayPolicy)
+	}
+
+	deadline := msg(lang, "settings.none")
+	if !s.Deadline.IsZero() {
+		deadline = msg(lang, "settings.deadline", s.Deadline.In(s.location()).Format("2006/1/2"), s.DeadlineBefore)
+	}
+
+	notify := "off"
+	if s.NotifyChanges {
+		notify = "on"
+	}
+
+	return msg(lang, "settings.message", s.DisplayName, event, s.RemindTime, timeZone, quiet, holiday, deadline, notify)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+/**
+ * `show settings`コマンド判定
+ */
+func TestIsShowSettingsCommand(t *testing.T) {
+	type testParameter struct {
+		text     string
+		expected bool
+	}
+	testCases := []testParameter{{
+		text:     "show settings",
+		expected: true,
+	}, {
+		text:     "  show setting \n\n", // 前後にノイズ、単数形でもtrue
+		expected: true,
+	}, {
+		text:     "show", //コマンド誤り
+		expected: false,
+	}}
+
+	for _, current := range testCases {
+		actual := isShowSettingsCommand(current.text)
+		if actual != current.expected {
+			t.Errorf("Illegal return value. text:%v, returnd:%v", current.text, actual)
+		}
+	}
+}
+
+/**
+ * 現在の設定を表示するメッセージの組み立て
+ */
+func TestConstructSettingsMessage(t *testing.T) {
+	tz, _ := time.LoadLocation("Asia/Tokyo")
+	testdata := subscriber{
+		DisplayName:    "テストグループ",
+		ChouseisanHash: "3f7ffd73ba174332ae05bd363eba8e71",
+		RemindTime:     8,
+		QuietStart:     22,
+		QuietEnd:       7,
+		HolidayPolicy:  holidayPolicyShift,
+		Deadline:       time.Date(2016, time.December, 20, 0, 0, 0, 0, tz),
+		DeadlineBefore: 1,
+	}
+	expected := "現在の設定\n\n" +
+		"グループ名: テストグループ\n" +
+		"調整さんイベント: https://chouseisan.com/s?h=3f7ffd73ba174332ae05bd363eba8e71\n" +
+		"リマインド: 3日前と当日の8:00（Asia/Tokyo）\n" +
+		"送信しない時間帯: 22:00〜7:00\n" +
+		"土日祝日: 直前の平日に前倒し\n" +
+		"回答期限: 2016/12/20（1日前にお知らせ）\n" +
+		"出欠変更の通知: off"
+	if actual := testdata.constructSettingsMessage(); actual != expected {
+		t.Errorf("Unmatch settings message\nexpect:\n%v\nactual:\n%v", expected, actual)
+	}
+}
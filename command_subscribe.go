@@ -0,0 +1,59 @@
+package main
+
+import (
+	"regexp"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/appengine/datastore"
+	"google.golang.org/appengine/log"
+)
+
+/**
+ * `subscribe me`、`unsubscribe me`コマンドであれば、個別通知を受け取るか（subscribe/unsubscribe）を返す
+ */
+func isSubscribeMeCommand(command string) (bool, bool) {
+	pattern := regexp.MustCompile(`^[ \n]*(subscribe|unsubscribe) me[ \n]*$`)
+	matches := pattern.FindStringSubmatch(command)
+	if len(matches) == 2 {
+		return true, matches[1] == "subscribe"
+	}
+	return false, false
+}
+
+/**
+ * 個別通知を受け取るよう設定したときのメッセージを返す
+ *
+ * 個別通知は回答期限の未回答リマインドと同じ日に送るので、回答期限が未設定であれば`set deadline`の案内を添える
+ */
+func constructSubscribeMeMessage(lang string, deadline time.Time) string {
+	message := msg(lang, "subscribe_me.on")
+	if deadline.IsZero() {
+		message += "\n\n" + msg(lang, "subscribe_me.no_deadline")
+	}
+	return message
+}
+
+/**
+ * メンバー紐付けエンティティに、個別通知を受け取るかを書き込む
+ *
+ * 紐付けがなければ（`iam`コマンド未実行）、datastore.ErrNoSuchEntityを返す
+ */
+func writePersonalReminder(c context.Context, mid string, uid string, personal bool) error {
+	var entity memberLink
+
+	key := memberLinkKey(c, mid, uid)
+	if err := datastore.Get(c, key, &entity); err != nil {
+		if err != datastore.ErrNoSuchEntity {
+			log.Errorf(c, "Error occurred at get MemberLink entity. mid:%v uid:%v err:%v", mid, uid, err)
+		}
+		return err
+	}
+
+	entity.Personal = personal
+	if _, err := datastore.Put(c, key, &entity); err != nil {
+		log.Errorf(c, "Error occurred at put MemberLink entity. mid:%v uid:%v err:%v", mid, uid, err)
+		return err
+	}
+	return nil
+}
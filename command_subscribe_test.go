@@ -0,0 +1,123 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"google.golang.org/appengine"
+	"google.golang.org/appengine/aetest"
+	"google.golang.org/appengine/datastore"
+)
+
+/**
+ * `subscribe me`、`unsubscribe me`コマンド判定
+ */
+func TestIsSubscribeMeCommand(t *testing.T) {
+	type testParameter struct {
+		text             string
+		expectedIs       bool
+		expectedPersonal bool
+	}
+	testCases := []testParameter{{
+		text:             "subscribe me",
+		expectedIs:       true,
+		expectedPersonal: true,
+	}, {
+		text:             "  unsubscribe me\n\n", // 前後にノイズがあってもtrue
+		expectedIs:       true,
+		expectedPersonal: false,
+	}, {
+		text:             "subscribe", // 対象の指定なし
+		expectedIs:       false,
+		expectedPersonal: false,
+	}}
+
+	for _, current := range testCases {
+		actualIs, actualPersonal := isSubscribeMeCommand(current.text)
+		if actualIs != current.expectedIs {
+			t.Errorf("Illegal return value. text:%v, returnd:%v", current.text, actualIs)
+		}
+		if actualPersonal != current.expectedPersonal {
+			t.Errorf("Illegal return value. text:%v, returnd:%v", current.text, actualPersonal)
+		}
+	}
+}
+
+/**
+ * 個別通知を受け取るよう設定したときのメッセージ（回答期限が未設定であれば案内を添える）
+ */
+func TestConstructSubscribeMeMessage(t *testing.T) {
+	type testParameter struct {
+		lang     string
+		deadline time.Time
+		expected string
+	}
+	testCases := []testParameter{{
+		lang:     langJa,
+		deadline: time.Date(2016, time.December, 20, 0, 0, 0, 0, time.UTC),
+		expected: "回答期限が近づいたら、出欠が未入力/△の日程を1:1トークでお知らせします（BOTを友だち追加してください）",
+	}, {
+		lang:     langJa,
+		deadline: time.Time{},
+		expected: "回答期限が近づいたら、出欠が未入力/△の日程を1:1トークでお知らせします（BOTを友だち追加してください）\n\n" +
+			"回答期限が設定されていないため、まだお知らせは届きません。「/set deadline 12/20」のように回答期限を設定してください",
+	}, {
+		lang:     langEn,
+		deadline: time.Time{},
+		expected: "Dates you have not answered or answered △ will be sent to you in a 1:1 chat near the deadline (add the bot as a friend)\n\n" +
+			"No answer deadline is set, so nothing will be sent yet. Set one like \"/set deadline 12/20\"",
+	}}
+
+	for _, current := range testCases {
+		if actual := constructSubscribeMeMessage(current.lang, current.deadline); actual != current.expected {
+			t.Errorf("Unmatch message\nexpect:\n%v\nactual:\n%v", current.expected, actual)
+		}
+	}
+}
+
+/**
+ * データストアに個別通知の設定を書き込む関数のテスト
+ */
+func TestWritePersonalReminder(t *testing.T) {
+	opt := aetest.Options{StronglyConsistentDatastore: true} //データストアに即反映
+	instance, err := aetest.NewInstance(&opt)
+	if err != nil {
+		t.Fatalf("Failed to create aetest instance: %v", err)
+	}
+	defer instance.Close()
+
+	// Contextが必要なので、ダミーのhttp.Request
+	req, err := instance.NewRequest("POST", "/task/analyzecommand", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := appengine.NewContext(req)
+
+	mid := "C00000000000000000000000000000000"
+	uid := "U00000000000000000000000000000001"
+
+	// 紐付けがなければErrNoSuchEntity
+	if err := writePersonalReminder(c, mid, uid, true); err != datastore.ErrNoSuchEntity {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	// execute
+	if err := writeMemberLink(c, mid, uid, "電次郎", "でんじろう"); err != nil {
+		t.Fatal(err)
+	}
+	if err := writePersonalReminder(c, mid, uid, true); err != nil {
+		t.Fatal(err)
+	}
+
+	// 名前を変更しても、個別通知の設定は引き継がれること
+	if err := writeMemberLink(c, mid, uid, "電三太郎", "でんじろう"); err != nil {
+		t.Fatal(err)
+	}
+	var actualEntity memberLink
+	if err = datastore.Get(c, memberLinkKey(c, mid, uid), &actualEntity); err != nil {
+		t.Fatal(err)
+	}
+	if !actualEntity.Personal {
+		t.Errorf("Unmatch entitiy's personal. personal='%v'", actualEntity.Personal)
+	}
+}
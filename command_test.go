@@ -0,0 +1,218 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/line/line-bot-sdk-go/linebot"
+)
+
+/**
+ * メッセージの先頭のBOTへのメンションの判定と、メンションを除いたテキストの取り出し
+ */
+func TestTrimBotMention(t *testing.T) {
+	const botUserID = "U0000000000000000000000000000b0t"
+	type testParameter struct {
+		text         string
+		mentionees   []*linebot.Mentionee
+		expectedIs   bool
+		expectedText string
+	}
+	testCases := []testParameter{{
+		text:         "@調整さんリマインダ show settings",
+		mentionees:   []*linebot.Mentionee{{Index: 0, Length: 10, UserID: botUserID}},
+		expectedIs:   true,
+		expectedText: " show settings",
+	}, {
+		text:         " @🤖リマインダ /whois", // 前の空白、サロゲートペアを含む名前（UTF-16で数える）
+		mentionees:   []*linebot.Mentionee{{Index: 1, Length: 8, UserID: botUserID}},
+		expectedIs:   true,
+		expectedText: " /whois",
+	}, {
+		text:         "@電次郎 @調整さんリマインダ show settings", // 先頭以外のメンション
+		mentionees:   []*linebot.Mentionee{{Index: 0, Length: 4, UserID: "U00000000000000000000000000000002"}, {Index: 5, Length: 10, UserID: botUserID}},
+		expectedIs:   false,
+		expectedText: "@電次郎 @調整さんリマインダ show settings",
+	}, {
+		text:         "@調整さんリマインダー show settings", // BOT以外へのメンション
+		mentionees:   []*linebot.Mentionee{{Index: 0, Length: 11, UserID: "U00000000000000000000000000000002"}},
+		expectedIs:   false,
+		expectedText: "@調整さんリマインダー show settings",
+	}, {
+		text:         "@調整さんリマインダ show settings", // メンションなし（名前を手で入力した場合）
+		mentionees:   nil,
+		expectedIs:   false,
+		expectedText: "@調整さんリマインダ show settings",
+	}}
+
+	for _, current := range testCases {
+		actualIs, actualText := trimBotMention(current.text, current.mentionees, botUserID)
+		if actualIs != current.expectedIs {
+			t.Errorf("Illegal return value. text:%v, returnd:%v", current.text, actualIs)
+		}
+		if actualText != current.expectedText {
+			t.Errorf("Illegal return value. text:%v, returnd:%v", current.text, actualText)
+		}
+	}
+
+	// BOTのユーザIDが取得できなければ、メンションは受け付けない
+	if actualIs, _ := trimBotMention("@ show settings", []*linebot.Mentionee{{Index: 0, Length: 1}}, ""); actualIs {
+		t.Errorf("Illegal return value. text:%v, returnd:%v", "@ show settings", actualIs)
+	}
+}
+
+/**
+ * コマンドの接頭辞の判定と、接頭辞を除いたコマンドの取り出し
+ */
+func TestParseCommand(t *testing.T) {
+	type testParameter struct {
+		text            string
+		mentioned       bool
+		expectedIs      bool
+		expectedCommand string
+	}
+	testCases := []testParameter{{
+		text:            "/set name テストグループ",
+		expectedIs:      true,
+		expectedCommand: "set name テストグループ",
+	}, {
+		text:            "  \n/ help　", // 前後と接頭辞の後の空白（全角を含む）は除く
+		expectedIs:      true,
+		expectedCommand: "help",
+	}, {
+		text:            "／remind now", // 全角スラッシュ
+		expectedIs:      true,
+		expectedCommand: "remind now",
+	}, {
+		text:            " show settings", // メンション
+		mentioned:       true,
+		expectedIs:      true,
+		expectedCommand: "show settings",
+	}, {
+		text:            "　/whois", // メンションに続くスラッシュ
+		mentioned:       true,
+		expectedIs:      true,
+		expectedCommand: "whois",
+	}, {
+		text:            "", // メンションだけ
+		mentioned:       true,
+		expectedIs:      false,
+		expectedCommand: "",
+	}, {
+		text:            " https://chouseisan.com/s?h=3f7ffd73ba174332ae05bd363eba8e71", // メンションに続く調整さんのURLは、貼られたURLとして扱う
+		mentioned:       true,
+		expectedIs:      false,
+		expectedCommand: "",
+	}, {
+		text:            " https://lin.ee/AbCdEfG", // LINEが短縮したURLも同じ
+		mentioned:       true,
+		expectedIs:      false,
+		expectedCommand: "",
+	}, {
+		text:            " set chouseisan https://lin.ee/AbCdEfG", // 短縮したURLの`set chouseisan`
+		mentioned:       true,
+		expectedIs:      true,
+		expectedCommand: "set chouseisan https://lin.ee/AbCdEfG",
+	}, {
+		text:            " set chouseisan https://chouseisan.com/s?h=3f7ffd73ba174332ae05bd363eba8e71", // メンションに続く`set chouseisan`
+		mentioned:       true,
+		expectedIs:      true,
+		expectedCommand: "set chouseisan https://chouseisan.com/s?h=3f7ffd73ba174332ae05bd363eba8e71",
+	}, {
+		text:            "show settings", // メンションなし
+		expectedIs:      false,
+		expectedCommand: "",
+	}, {
+		text:            "/",
+		expectedIs:      true,
+		expectedCommand: "",
+	}, {
+		text:            "", // 空のメッセージ
+		expectedIs:      false,
+		expectedCommand: "",
+	}, {
+		text:            "明日の飲み会は19時から/駅前集合", // 途中のスラッシュ
+		expectedIs:      false,
+		expectedCommand: "",
+	}}
+
+	for _, current := range testCases {
+		actualIs, actualCommand := parseCommand(current.text, current.mentioned)
+		if actualIs != current.expectedIs {
+			t.Errorf("Illegal return value. text:%v, returnd:%v", current.text, actualIs)
+		}
+		if actualCommand != current.expectedCommand {
+			t.Errorf("Illegal return value. text:%v, returnd:%v", current.text, actualCommand)
+		}
+	}
+}
+
+/**
+ * コマンドの引数の分割（引用符で囲んだ部分はひとつの引数）
+ */
+func TestSplitCommandArgs(t *testing.T) {
+	type testParameter struct {
+		text     string
+		expected []string
+	}
+	testCases := []testParameter{{
+		text:     "set name テストグループ",
+		expected: []string{"set", "name", "テストグループ"},
+	}, {
+		text:     `set name "A B"`,
+		expected: []string{"set", "name", "A B"},
+	}, {
+		text:     "set name “A B”", // 自動変換された全角の引用符
+		expected: []string{"set", "name", "A B"},
+	}, {
+		text:     `"A B"C  D`, // 引用符に続く文字は同じ引数
+		expected: []string{"A BC", "D"},
+	}, {
+		text:     `"A B`, // 閉じていない引用符
+		expected: []string{"A B"},
+	}, {
+		text:     `""`, // 空の引数
+		expected: []string{""},
+	}, {
+		text:     "",
+		expected: []string{},
+	}}
+
+	for _, current := range testCases {
+		if actual := splitCommandArgs(current.text); !reflect.DeepEqual(actual, current.expected) {
+			t.Errorf("Illegal return value. text:%v, returnd:%q", current.text, actual)
+		}
+	}
+}
+
+/**
+ * 名前などの自由入力の引数（全体を囲む引用符のみ外し、空白はそのまま残す）
+ */
+func TestCommandArg(t *testing.T) {
+	type testParameter struct {
+		text     string
+		expected string
+	}
+	testCases := []testParameter{{
+		text:     " テスト  グループ ",
+		expected: "テスト  グループ",
+	}, {
+		text:     `"A  B"`,
+		expected: "A  B",
+	}, {
+		text:     "“A B”", // 自動変換された全角の引用符
+		expected: "A B",
+	}, {
+		text:     `A "B C"`, // 一部だけの引用符は外さない
+		expected: `A "B C"`,
+	}, {
+		text:     `"A" "B"`, // 複数の引数は外さない
+		expected: `"A" "B"`,
+	}}
+
+	for _, current := range testCases {
+		if actual := commandArg(current.text); actual != current.expected {
+			t.Errorf("Illegal return value. text:%v, returnd:%v", current.text, actual)
+		}
+	}
+}
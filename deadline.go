@@ -0,0 +1,91 @@
+package main
+
+import (
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/line/line-bot-sdk-go/linebot"
+
+	"google.golang.org/appengine/log"
+)
+
+/**
+ * 調整さんのメンバーの名前を、csvの列順で返す
+ */
+func (m scheduleMap) memberNames() []string {
+	names := []string{}
+	for _, s := range m {
+		if len(s.Names) > len(names) {
+			names = s.Names
+		}
+	}
+	return names
+}
+
+/**
+ * すべての候補日程で出欠が未入力のメンバーの名前を、csvの列順で返す
+ */
+func (m scheduleMap) unansweredNames() []string {
+	result := []string{}
+	for _, name := range m.memberNames() {
+		answered := false
+		for _, s := range m {
+			if s.Answers[name] != "" {
+				answered = true
+				break
+			}
+		}
+		if !answered {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+/**
+ * todayが、回答期限の未回答リマインドを送信する日であればtrueを返す
+ */
+func (s *subscriber) isDeadlineRemindDay(today time.Time) bool {
+	if s.Deadline.IsZero() {
+		return false
+	}
+	tz := s.location()
+	deadline := s.Deadline.In(tz)
+	remindDay := time.Date(deadline.Year(), deadline.Month(), deadline.Day()-s.DeadlineBefore, 0, 0, 0, 0, tz)
+	today = today.In(tz)
+	return remindDay.Equal(time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, tz))
+}
+
+/**
+ * 回答期限の未回答リマインドのメッセージを組み立てて返す
+ */
+func constructDeadlineMessage(lang string, deadline time.Time, before int, names []string, hash string) string {
+	message := msg(lang, "deadline.today", int(deadline.Month()), deadline.Day())
+	if before > 0 {
+		message = msg(lang, "deadline.before", int(deadline.Month()), deadline.Day(), before)
+	}
+	return message + "\n\n" + msg(lang, "deadline.unanswered", len(names), truncateNames(names, ",", maxNamesLength)) +
+		"\n\n" + msg(lang, "deadline.enter", "https://chouseisan.com/s?h="+hash)
+}
+
+/**
+ * 回答期限のN日前であれば、すべての候補日程で未入力のメンバーを列挙してPush Messageを送信する
+ */
+func remindDeadline(c context.Context, bot *linebot.Client, current *subscriber, m scheduleMap, today time.Time) {
+	if !current.isDeadlineRemindDay(today) {
+		return
+	}
+
+	names := m.unansweredNames()
+	if len(names) == 0 {
+		log.Infof(c, "All members answered before deadline. subscriber:%v", current.DisplayName)
+		return
+	}
+
+	log.Infof(c, "Remind deadline! subscriber:%v unanswered:%v", current.DisplayName, len(names))
+	message := constructDeadlineMessage(current.lang(), current.Deadline.In(current.location()), current.DeadlineBefore, names, current.ChouseisanHash)
+	if err := sendPush(bot, current.MID, newTextMessages(message)); err != nil {
+		log.Errorf(c, "Error occurred at remind deadline. subscriber:%v, err: %v", current.DisplayName, err)
+	}
+}
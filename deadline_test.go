@@ -0,0 +1,122 @@
+package main
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/appengine/aetest"
+)
+
+/**
+ * すべての候補日程で未入力のメンバーの抽出（△のみの入力は回答済み扱い）
+ */
+func TestUnansweredNames(t *testing.T) {
+	c, done, err := aetest.NewContext()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer done()
+
+	//テストデータはファイルから読む
+	testdata, err := os.Open("testdata/chouseisan/unanswered.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer testdata.Close()
+
+	tz, _ := time.LoadLocation("Asia/Tokyo")
+	today := time.Date(2016, time.December, 1, 0, 0, 0, 0, tz)
+	m := parseCsv(c, testdata, today)
+
+	expected := []string{"電三太郎"}
+	if actual := m.unansweredNames(); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Unmatch unanswered names: %v", actual)
+	}
+}
+
+/**
+ * 回答期限の未回答リマインドを送信する日の判定
+ */
+func TestIsDeadlineRemindDay(t *testing.T) {
+	type testParameter struct {
+		before   int
+		today    time.Time
+		expected bool
+	}
+	tz, _ := time.LoadLocation("Asia/Tokyo")
+	deadline := time.Date(2016, time.December, 20, 0, 0, 0, 0, tz)
+	testCases := []testParameter{{
+		before:   1,
+		today:    time.Date(2016, time.December, 19, 8, 0, 0, 0, tz),
+		expected: true,
+	}, {
+		before:   1,
+		today:    time.Date(2016, time.December, 20, 8, 0, 0, 0, tz),
+		expected: false,
+	}, {
+		before:   0, // 期限当日
+		today:    time.Date(2016, time.December, 20, 8, 0, 0, 0, tz),
+		expected: true,
+	}, {
+		before:   3,
+		today:    time.Date(2016, time.December, 17, 8, 0, 0, 0, tz),
+		expected: true,
+	}}
+
+	for _, current := range testCases {
+		s := subscriber{Deadline: deadline, DeadlineBefore: current.before}
+		if actual := s.isDeadlineRemindDay(current.today); actual != current.expected {
+			t.Errorf("Illegal return value. before:%v, today:%v, returnd:%v", current.before, current.today, actual)
+		}
+	}
+
+	// 期限が設定されていなければ、リマインドしない
+	s := subscriber{}
+	if s.isDeadlineRemindDay(time.Date(2016, time.December, 19, 8, 0, 0, 0, tz)) {
+		t.Error("Remind without deadline")
+	}
+}
+
+/**
+ * 回答期限の未回答リマインドのメッセージ組み立て
+ */
+func TestConstructDeadlineMessage(t *testing.T) {
+	tz, _ := time.LoadLocation("Asia/Tokyo")
+	deadline := time.Date(2016, time.December, 20, 0, 0, 0, 0, tz)
+	type testParameter struct {
+		lang     string
+		before   int
+		expected string
+	}
+	testCases := []testParameter{{
+		lang:   langJa,
+		before: 1,
+		expected: "12/20の回答期限まであと1日です\n\n" +
+			"まだ出欠を入力していないメンバー: 2名(電一,電三太郎)" +
+			"\n\n出欠の入力は「調整さん」へ\n" +
+			"https://chouseisan.com/s?h=3f7ffd73ba174332ae05bd363eba8e71",
+	}, {
+		lang:   langJa,
+		before: 0,
+		expected: "12/20の回答期限は今日です\n\n" +
+			"まだ出欠を入力していないメンバー: 2名(電一,電三太郎)" +
+			"\n\n出欠の入力は「調整さん」へ\n" +
+			"https://chouseisan.com/s?h=3f7ffd73ba174332ae05bd363eba8e71",
+	}, {
+		lang:   langEn,
+		before: 1,
+		expected: "1 day(s) left until the answer deadline 12/20\n\n" +
+			"Members who have not answered yet: 2 (電一,電三太郎)" +
+			"\n\nEnter your attendance on chouseisan\n" +
+			"https://chouseisan.com/s?h=3f7ffd73ba174332ae05bd363eba8e71",
+	}}
+
+	for _, current := range testCases {
+		actual := constructDeadlineMessage(current.lang, deadline, current.before, []string{"電一", "電三太郎"}, "3f7ffd73ba174332ae05bd363eba8e71")
+		if actual != current.expected {
+			t.Errorf("Unmatch message\nexpect:\n%v\nactual:\n%v", current.expected, actual)
+		}
+	}
+}
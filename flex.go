@@ -0,0 +1,142 @@
+package main
+
+import (
+	"encoding/json"
+
+	"github.com/line/line-bot-sdk-go/linebot"
+)
+
+// Flex Messageのコンポーネント（box、text、buttonなど。使用するプロパティのみ定義）
+type flexComponent struct {
+	Type     string          `json:"type"`
+	Layout   string          `json:"layout,omitempty"`
+	Contents []flexComponent `json:"contents,omitempty"`
+	Text     string          `json:"text,omitempty"`
+	Size     string          `json:"size,omitempty"`
+	Weight   string          `json:"weight,omitempty"`
+	Color    string          `json:"color,omitempty"`
+	Align    string          `json:"align,omitempty"`
+	Wrap     bool            `json:"wrap,omitempty"`
+	Flex     *int            `json:"flex,omitempty"`
+	Spacing  string          `json:"spacing,omitempty"`
+	Margin   string          `json:"margin,omitempty"`
+	Style    string          `json:"style,omitempty"`
+	Action   *flexAction     `json:"action,omitempty"`
+}
+
+// Flex Messageのアクション（uri、postback）
+type flexAction struct {
+	Type        string `json:"type"`
+	Label       string `json:"label"`
+	URI         string `json:"uri,omitempty"`
+	Data        string `json:"data,omitempty"`
+	DisplayText string `json:"displayText,omitempty"`
+}
+
+// Flex Messageのバブルコンテナ
+type flexBubble struct {
+	Type   string         `json:"type"`
+	Header *flexComponent `json:"header,omitempty"`
+	Body   *flexComponent `json:"body,omitempty"`
+	Footer *flexComponent `json:"footer,omitempty"`
+}
+
+// 出欠ごとの表示ラベルと色
+var answerRows = []struct {
+	Answer string
+	Label  string // メッセージカタログのキー
+	Color  string
+}{
+	{"○", "flex.present", "#1DB446"},
+	{"△", "flex.maybe", "#FF9F0A"},
+	{"×", "flex.absent", "#E5484D"},
+	{"", "flex.unanswered", "#999999"},
+}
+
+/**
+ * 出欠ごとに、メンバーの名前をcsvの列順で返す
+ */
+func (s *schedule) namesByAnswer(answer string) []string {
+	names := []string{}
+	for _, name := range s.Names {
+		if s.Answers[name] == answer {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+/**
+ * リマインドのフッタ（調整さんのページを開いて出欠を入力するボタン）を組み立てて返す
+ *
+ * 調整さんは出欠登録のAPIを公開していないため、LINEから出欠を登録するボタンは付けない
+ */
+func (s *schedule) constructAnswerFooter(lang string, hash string) flexComponent {
+	return flexComponent{
+		Type:    "box",
+		Layout:  "vertical",
+		Spacing: "sm",
+		Contents: []flexComponent{{
+			Type:   "button",
+			Style:  "primary",
+			Action: &flexAction{Type: "uri", Label: msg(lang, "flex.open"), URI: "https://chouseisan.com/s?h=" + hash},
+		}},
+	}
+}
+
+/**
+ * リマインド用のFlex Messageバブルを組み立てて返す
+ *
+ * ヘッダにイベント名と日程、ボディに出欠ごとの人数と名前、フッタに○/△/×で回答するボタンと調整さんへのボタンを配置する
+ */
+func (s *schedule) constructFlexBubble(lang string, hash string) flexBubble {
+	zero := 0
+
+	header := flexComponent{Type: "box", Layout: "vertical", Contents: []flexComponent{}}
+	if len(s.EventTitle) > 0 {
+		header.Contents = append(header.Contents, flexComponent{Type: "text", Text: s.EventTitle, Size: "sm", Color: "#888888", Wrap: true})
+	}
+	header.Contents = append(header.Contents, flexComponent{Type: "text", Text: s.DateString, Size: "xl", Weight: "bold", Wrap: true})
+
+	body := flexComponent{Type: "box", Layout: "vertical", Spacing: "md", Contents: []flexComponent{}}
+	for _, row := range answerRows {
+		names := s.namesByAnswer(row.Answer)
+		contents := []flexComponent{{
+			Type:   "box",
+			Layout: "horizontal",
+			Contents: []flexComponent{
+				{Type: "text", Text: msg(lang, row.Label), Weight: "bold", Color: row.Color, Flex: &zero},
+				{Type: "text", Text: msg(lang, "flex.count", len(names)), Align: "end", Color: row.Color},
+			},
+		}}
+		if len(names) > 0 {
+			contents = append(contents, flexComponent{Type: "text", Text: truncateNames(names, ", ", maxNamesLength), Size: "sm", Color: "#666666", Wrap: true})
+		}
+		body.Contents = append(body.Contents, flexComponent{Type: "box", Layout: "vertical", Contents: contents})
+	}
+
+	footer := s.constructAnswerFooter(lang, hash)
+	return flexBubble{Type: "bubble", Header: &header, Body: &body, Footer: &footer}
+}
+
+/**
+ * リマインド用のFlex Messageを組み立てて返す。代替テキストはconstructSummary（上限の文字数で切り詰める）
+ */
+func (s *schedule) constructReminderMessage(lang string, hash string) (linebot.SendingMessage, error) {
+	return newFlexMessage(s.constructSummary(lang, hash), s.constructFlexBubble(lang, hash))
+}
+
+/**
+ * バブルからFlex Messageを組み立てて返す。代替テキストは上限の文字数で切り詰める
+ */
+func newFlexMessage(altText string, b flexBubble) (linebot.SendingMessage, error) {
+	bubble, err := json.Marshal(b)
+	if err != nil {
+		return nil, err
+	}
+	container, err := linebot.UnmarshalFlexMessageJSON(bubble)
+	if err != nil {
+		return nil, err
+	}
+	return linebot.NewFlexMessage(truncateText(altText, maxAltTextLength), container), nil
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+/**
+ * リマインド用のFlex Messageバブル組み立てのテスト（JSONをゴールデンファイルと比較）
+ */
+func TestConstructFlexBubble(t *testing.T) {
+	tz, _ := time.LoadLocation("Asia/Tokyo")
+	testdata := schedule{
+		EventTitle: "調整さんリマインダテストデータ",
+		Date:       time.Date(2016, time.December, 24, 0, 0, 0, 0, tz),
+		DateString: "12/24(土) 19:00〜",
+		Names:      []string{"電一", "電次郎", "電三太郎", "電四郎", "電五郎"},
+		Answers: map[string]string{
+			"電一":   "△",
+			"電次郎":  "×",
+			"電三太郎": "○",
+			"電四郎":  "○",
+			"電五郎":  "",
+		},
+	}
+	actual, err := json.MarshalIndent(testdata.constructFlexBubble(langJa, "3f7ffd73ba174332ae05bd363eba8e71"), "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := strings.TrimSpace(readFile(t, "testdata/linebot/flex_reminder.json"))
+	if string(actual) != expected {
+		t.Errorf("Unmatch flex bubble\nexpect:\n%v\nactual:\n%v", expected, string(actual))
+	}
+}
+
+/**
+ * フッタは、調整さんのページを開くボタンだけにする（○/△/×ボタンは表示しない）
+ */
+func TestConstructAnswerFooter(t *testing.T) {
+	tz, _ := time.LoadLocation("Asia/Tokyo")
+	testdata := schedule{
+		Date:       time.Date(2016, time.December, 24, 0, 0, 0, 0, tz),
+		DateString: "12/24(土) 19:00〜",
+		Names:      []string{"電一"},
+		Answers:    map[string]string{"電一": "○"},
+	}
+	footer := testdata.constructAnswerFooter(langJa, "3f7ffd73ba174332ae05bd363eba8e71")
+	if len(footer.Contents) != 1 {
+		t.Fatalf("Unmatch footer contents: %+v", footer.Contents)
+	}
+	if action := footer.Contents[0].Action; action == nil || action.Type != "uri" || action.URI != "https://chouseisan.com/s?h=3f7ffd73ba174332ae05bd363eba8e71" {
+		t.Errorf("Unmatch footer action: %+v", action)
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import "time"
+
+/**
+ * 第n月曜日であればtrueを返す（ハッピーマンデー制度）
+ */
+func isNthMonday(day int, weekday time.Weekday, n int) bool {
+	return weekday == time.Monday && (day-1)/7 == n-1
+}
+
+/**
+ * 春分日・秋分日を返す（1980〜2099年の近似式）
+ */
+func equinoxDay(year int, base float64) int {
+	return int(base + 0.242194*float64(year-1980) - float64((year-1980)/4))
+}
+
+/**
+ * 「国民の祝日に関する法律」の規定から計算した祝日であれば、その名前を返す（振替休日、国民の休日は含まない）
+ *
+ * 特例による一度限りの祝日や移動は計算できないので、祝日CSVの範囲外の年だけに使う
+ */
+func nationalHolidayName(date time.Time) string {
+	year, month, day := date.Date()
+	weekday := date.Weekday()
+
+	name := ""
+	switch month {
+	case time.January:
+		if day == 1 {
+			name = "元日"
+		} else if isNthMonday(day, weekday, 2) {
+			name = "成人の日"
+		}
+	case time.February:
+		if day == 11 {
+			name = "建国記念の日"
+		} else if day == 23 && year >= 2020 {
+			name = "天皇誕生日"
+		}
+	case time.March:
+		if day == equinoxDay(year, 20.8431) {
+			name = "春分の日"
+		}
+	case time.April:
+		if day == 29 {
+			name = "昭和の日"
+		}
+	case time.May:
+		switch day {
+		case 3:
+			name = "憲法記念日"
+		case 4:
+			name = "みどりの日"
+		case 5:
+			name = "こどもの日"
+		}
+	case time.July:
+		if isNthMonday(day, weekday, 3) {
+			name = "海の日"
+		}
+	case time.August:
+		if day == 11 && year >= 2016 {
+			name = "山の日"
+		}
+	case time.September:
+		if isNthMonday(day, weekday, 3) {
+			name = "敬老の日"
+		} else if day == equinoxDay(year, 23.2488) {
+			name = "秋分の日"
+		}
+	case time.October:
+		if isNthMonday(day, weekday, 2) {
+			if year >= 2020 {
+				name = "スポーツの日"
+			} else {
+				name = "体育の日"
+			}
+		}
+	case time.November:
+		if day == 3 {
+			name = "文化の日"
+		} else if day == 23 {
+			name = "勤労感謝の日"
+		}
+	case time.December:
+		if day == 23 && year <= 2018 {
+			name = "天皇誕生日"
+		}
+	}
+
+	return name
+}
+
+/**
+ * 日本の祝日・休日であれば、その名前を返す。祝日でなければ空文字を返す
+ *
+ * 内閣府の祝日CSVを埋め込んだデータ（holiday_data.go）で判定する。振替休日と国民の休日は、CSVと同じく「休日」とする
+ * CSVの範囲外の年は、法律の規定から計算する（calculatedHolidayName）
+ */
+func japaneseHolidayName(t time.Time) string {
+	if year := t.Year(); year >= cabinetOfficeHolidaysFrom && year <= cabinetOfficeHolidaysTo {
+		return cabinetOfficeHolidays[t.Format("2006-01-02")]
+	}
+	return calculatedHolidayName(t)
+}
+
+/**
+ * 法律の規定から計算した祝日・休日であれば、その名前を返す。祝日でなければ空文字を返す
+ *
+ * 振替休日（祝日が日曜日の場合、その後の最初の平日）と国民の休日（祝日に挟まれた平日）を含む
+ */
+func calculatedHolidayName(t time.Time) string {
+	date := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+
+	if name := nationalHolidayName(date); name != "" {
+		return name
+	}
+
+	//振替休日：直前に連続する祝日のいずれかが日曜日
+	for prev := date.AddDate(0, 0, -1); nationalHolidayName(prev) != ""; prev = prev.AddDate(0, 0, -1) {
+		if prev.Weekday() == time.Sunday {
+			return "振替休日"
+		}
+	}
+
+	//国民の休日：前日と翌日が祝日
+	if nationalHolidayName(date.AddDate(0, 0, -1)) != "" && nationalHolidayName(date.AddDate(0, 0, 1)) != "" {
+		return "国民の休日"
+	}
+
+	return ""
+}
+
+/**
+ * 平日（土日および日本の祝日・休日でない日）であればtrueを返す
+ */
+func isBusinessDay(t time.Time) bool {
+	if t.Weekday() == time.Saturday || t.Weekday() == time.Sunday {
+		return false
+	}
+	return japaneseHolidayName(t) == ""
+}
@@ -0,0 +1,31 @@
+# 内閣府「国民の祝日」CSV（UTF-8、改行はLFに変換済み）から、holiday_data.goを生成する（`make holidays`から実行する）
+# -v from=年 を指定すると、その年より前の祝日は出力しない（CSVは1955年からあるが、過去の年はリマインドに使わないため）
+BEGIN {
+	FS = ","
+	print "// Code generated by `make holidays`. DO NOT EDIT."
+	print ""
+	print "package main"
+	print ""
+	print "// 内閣府「国民の祝日」CSV（https://www8.cao.go.jp/chosei/shukujitsu/syukujitsu.csv）の祝日・休日。キーは\"2006-01-02\"形式"
+	print "var cabinetOfficeHolidays = map[string]string{"
+}
+NR > 1 && NF == 2 {
+	split($1, d, "/")
+	if (d[1] + 0 < from + 0) {
+		next
+	}
+	if (first == "") {
+		first = d[1]
+	}
+	last = d[1]
+	printf "\t\"%04d-%02d-%02d\": \"%s\",\n", d[1], d[2], d[3], $2
+}
+END {
+	print "}"
+	print ""
+	print "// 祝日CSVに含まれる年の範囲（範囲外の年は、法律の規定から計算する）"
+	print "const ("
+	printf "\tcabinetOfficeHolidaysFrom = %d\n", first
+	printf "\tcabinetOfficeHolidaysTo   = %d\n", last
+	print ")"
+}
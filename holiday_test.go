@@ -0,0 +1,132 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+/**
+ * 日本の祝日・休日の判定
+ */
+func TestJapaneseHolidayName(t *testing.T) {
+	type testParameter struct {
+		date     time.Time
+		expected string
+	}
+	tz, _ := time.LoadLocation("Asia/Tokyo")
+	testCases := []testParameter{{
+		date:     time.Date(2017, time.January, 1, 0, 0, 0, 0, tz),
+		expected: "元日",
+	}, {
+		date:     time.Date(2017, time.January, 2, 0, 0, 0, 0, tz), // 元日が日曜日（祝日CSVでは振替休日を「休日」とする）
+		expected: "休日",
+	}, {
+		date:     time.Date(2017, time.January, 9, 0, 0, 0, 0, tz), // 第2月曜日
+		expected: "成人の日",
+	}, {
+		date:     time.Date(2016, time.December, 23, 0, 0, 0, 0, tz),
+		expected: "天皇誕生日",
+	}, {
+		date:     time.Date(2019, time.December, 23, 0, 0, 0, 0, tz), // 2019年からは2月23日
+		expected: "",
+	}, {
+		date:     time.Date(2016, time.March, 20, 0, 0, 0, 0, tz),
+		expected: "春分の日",
+	}, {
+		date:     time.Date(2016, time.September, 22, 0, 0, 0, 0, tz),
+		expected: "秋分の日",
+	}, {
+		date:     time.Date(2019, time.April, 30, 0, 0, 0, 0, tz), // 祝日に挟まれた平日
+		expected: "休日",
+	}, {
+		date:     time.Date(2019, time.May, 1, 0, 0, 0, 0, tz), // 即位の日
+		expected: "休日（祝日扱い）",
+	}, {
+		date:     time.Date(2020, time.May, 6, 0, 0, 0, 0, tz), // 5月3日が日曜日
+		expected: "休日",
+	}, {
+		date:     time.Date(2020, time.July, 24, 0, 0, 0, 0, tz), // 東京オリンピックの特例
+		expected: "スポーツの日",
+	}, {
+		date:     time.Date(2020, time.October, 12, 0, 0, 0, 0, tz), // 特例により移動
+		expected: "",
+	}, {
+		date:     time.Date(2016, time.December, 1, 0, 0, 0, 0, tz), // 平日
+		expected: "",
+	}, {
+		date:     time.Date(cabinetOfficeHolidaysTo+3, time.January, 1, 0, 0, 0, 0, tz), // 祝日CSVの範囲外は計算する
+		expected: "元日",
+	}}
+
+	for _, current := range testCases {
+		actual := japaneseHolidayName(current.date)
+		if actual != current.expected {
+			t.Errorf("Illegal holiday name. date:%v, returnd:%v", current.date.Format("2006-01-02"), actual)
+		}
+	}
+}
+
+/**
+ * 法律の規定から計算した祝日・休日の判定（祝日CSVの範囲外の年に使う）
+ */
+func TestCalculatedHolidayName(t *testing.T) {
+	type testParameter struct {
+		date     time.Time
+		expected string
+	}
+	tz, _ := time.LoadLocation("Asia/Tokyo")
+	testCases := []testParameter{{
+		date:     time.Date(2030, time.January, 14, 0, 0, 0, 0, tz), // 第2月曜日
+		expected: "成人の日",
+	}, {
+		date:     time.Date(2030, time.March, 20, 0, 0, 0, 0, tz),
+		expected: "春分の日",
+	}, {
+		date:     time.Date(2033, time.January, 3, 0, 0, 0, 0, tz), // 元日が土曜日
+		expected: "",
+	}, {
+		date:     time.Date(2034, time.January, 2, 0, 0, 0, 0, tz), // 元日が日曜日
+		expected: "振替休日",
+	}, {
+		date:     time.Date(2032, time.September, 21, 0, 0, 0, 0, tz), // 敬老の日と秋分の日に挟まれた平日
+		expected: "国民の休日",
+	}}
+
+	for _, current := range testCases {
+		actual := calculatedHolidayName(current.date)
+		if actual != current.expected {
+			t.Errorf("Illegal holiday name. date:%v, returnd:%v", current.date.Format("2006-01-02"), actual)
+		}
+	}
+}
+
+/**
+ * 平日の判定（土日祝日でない）
+ */
+func TestIsBusinessDay(t *testing.T) {
+	type testParameter struct {
+		date     time.Time
+		expected bool
+	}
+	tz, _ := time.LoadLocation("Asia/Tokyo")
+	testCases := []testParameter{{
+		date:     time.Date(2016, time.December, 1, 0, 0, 0, 0, tz), // 木曜日
+		expected: true,
+	}, {
+		date:     time.Date(2016, time.December, 3, 0, 0, 0, 0, tz), // 土曜日
+		expected: false,
+	}, {
+		date:     time.Date(2016, time.December, 4, 0, 0, 0, 0, tz), // 日曜日
+		expected: false,
+	}, {
+		date:     time.Date(2016, time.November, 23, 0, 0, 0, 0, tz), // 勤労感謝の日（水曜日）
+		expected: false,
+	}}
+
+	for _, current := range testCases {
+		actual := isBusinessDay(current.date)
+		if actual != current.expected {
+			t.Errorf("Illegal return value. date:%v, returnd:%v", current.date.Format("2006-01-02"), actual)
+		}
+	}
+}
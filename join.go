@@ -15,20 +15,28 @@ import (
 
 /**
  * 送信者の表示名を取得する
- * ユーザしか取得できないので、ルームおよびグループではidをそのまま返す
+ * ユーザはプロフィール、グループはグループの概要から取得する。ルームは取得できないので、idをそのまま返す（取得に失敗した場合も同じ）
  */
 func getSenderName(c context.Context, bot *linebot.Client, from string) string {
 	if len(from) == 0 {
 		log.Warningf(c, "Parameter `mid` was not specified.")
 		return from
 	}
-	if from[0:1] == "U" {
+	switch from[0:1] {
+	case "U":
 		senderProfile, err := bot.GetProfile(from).Do()
 		if err != nil {
 			log.Warningf(c, "Error occurred at get sender profile. from: %v, err: %v", from, err)
 			return from
 		}
 		return senderProfile.DisplayName
+	case "C":
+		summary, err := bot.GetGroupSummary(from).Do()
+		if err != nil {
+			log.Warningf(c, "Error occurred at get group summary. from: %v, err: %v", from, err)
+			return from
+		}
+		return summary.GroupName
 	}
 	return from
 }
@@ -54,22 +62,28 @@ func joinWithContext(c context.Context, client *http.Client, w http.ResponseWrit
 			log.Errorf(c, "Error occurred at get Subscriber entity. mid:%v err: %v", mid, err)
 			return
 		}
-	} else {
+	} else if existEntity.LeftAt.IsZero() {
 		//すでに同一idのエンティティが存在する場合、以降の処理をスキップ
 		log.Infof(c, "Already exist entity of the same id. Maybe already joined to this group. mid:%v", mid)
 		return
+	} else if existEntity.isRestorable(time.Now()) {
+		//保持期間内に再招待された場合、以前の設定を引き継ぐ
+		rejoin(c, bot, key, &existEntity, r.FormValue("type"), r.FormValue("replyToken"))
+		return
 	}
 
 	//購読者プロファイルを取得
 	senderName := getSenderName(c, bot, mid)
 
-	//購読者を保存（リマインドタイミングのデフォルトは3日前の8:00）
+	//購読者を保存（リマインドタイミングのデフォルトは3日前の8:00。調整さんのURLを待つ初期設定から始める）
 	entity := subscriber{
 		DisplayName:    senderName,
 		MID:            mid,
 		ChouseisanHash: "",
 		RemindBefore:   3,
 		RemindTime:     8,
+		TimeZone:       defaultTimeZone,
+		Onboarding:     onboardingAwaitURL,
 	}
 	if _, err = datastore.Put(c, key, &entity); err != nil {
 		log.Errorf(c, "Error occurred at put subcriber to datastore. mid:%v, err: %v", mid, err)
@@ -89,13 +103,44 @@ func joinWithContext(c context.Context, client *http.Client, w http.ResponseWrit
 		return
 	}
 
-	// Reply message
-	message := "リマインダを登録しました！\n使いかたはこちらのページをご覧ください\nhttps://" + appengine.DefaultVersionHostname(c) + "/"
-	if _, err = bot.ReplyMessage(r.FormValue("replyToken"), linebot.NewTextMessage(message)).Do(); err != nil {
+	// Reply message（BOTの説明と、調整さんのURLの入力のお願い）
+	messages := constructWelcomeMessages(entity.lang(), appengine.DefaultVersionHostname(c))
+	if err = sendReply(bot, r.FormValue("replyToken"), messages); err != nil {
 		log.Errorf(c, "Error occurred at reply-message for follow/join. mid:%v, err: %v", mid, err)
 	}
 }
 
+/**
+ * 保持期間内に再招待された購読者の削除日時を消して、以前の設定を引き継いだことをリプライする
+ */
+func rejoin(c context.Context, bot *linebot.Client, key *datastore.Key, entity *subscriber, eventType string, token string) {
+	log.Infof(c, "Restore subscriber left at %v. mid:%v", entity.LeftAt, entity.MID)
+	entity.LeftAt = time.Time{}
+	if _, err := datastore.Put(c, key, entity); err != nil {
+		log.Errorf(c, "Error occurred at put subcriber to datastore. mid:%v, err: %v", entity.MID, err)
+		return
+	}
+
+	//ログエントリを追加
+	logEntity := logSubscriber{
+		DisplayName: entity.DisplayName,
+		MID:         entity.MID,
+		EventType:   eventType,
+		AddTime:     time.Now(),
+	}
+	logKey := datastore.NewIncompleteKey(c, "LogSubscriber", nil)
+	if _, err := datastore.Put(c, logKey, &logEntity); err != nil {
+		log.Errorf(c, "Error occurred at put log-subcriber to datastore. mid:%v, err: %v", entity.MID, err)
+		return
+	}
+
+	// Reply message（引き継いだ設定を表示する）
+	message := msg(entity.lang(), "join.restored") + "\n\n" + entity.constructSettingsMessage()
+	if err := sendReply(bot, token, withQuickReplies(newTextMessages(message), menuQuickReplyButtons(entity.lang()))); err != nil {
+		log.Errorf(c, "Error occurred at reply-message for re-join. mid:%v, err: %v", entity.MID, err)
+	}
+}
+
 /**
  * 友だち追加（データストアに購読者として登録）
  */
@@ -7,6 +7,7 @@ import (
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/thingful/httpmock"
 
@@ -37,7 +38,7 @@ func TestJoinGroupNormally(t *testing.T) {
 
 	// 評価する値
 	expectedMid := "C00000000000000000000000000000000" //グループなので先頭は"C"
-	expectedName := expectedMid                        //グループなので同じ値
+	expectedName := "テストグループ"                          //グループなので、グループの概要APIで取得に行く
 	expectedType := "join"
 
 	// http.Requestを生成
@@ -56,9 +57,16 @@ func TestJoinGroupNormally(t *testing.T) {
 	ctx := appengine.NewContext(req)
 	client := urlfetch.Client(ctx)
 
-	// LINEへのReply Messageリクエストをモックする
+	// LINEへのGet Group Summary、Reply Messageリクエストをモックする
 	httpmock.ActivateNonDefault(client)
 	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterStubRequest(
+		httpmock.NewStubRequest(
+			"GET",
+			"https://api.line.me/v2/bot/group/"+expectedMid+"/summary",
+			httpmock.NewStringResponder(200, `{"groupId":"`+expectedMid+`","groupName":"`+expectedName+`","pictureUrl":""}`),
+		),
+	)
 	httpmock.RegisterStubRequest(
 		httpmock.NewStubRequest(
 			"POST",
@@ -209,6 +217,94 @@ func TestJoinGroupExist(t *testing.T) {
 	}
 }
 
+/**
+ * 登録イベント：BOTを削除したグループに、保持期間内に再招待された場合
+ *
+ * 以前の設定を引き継いで、削除日時を消すこと
+ */
+func TestJoinGroupRestore(t *testing.T) {
+	opt := aetest.Options{StronglyConsistentDatastore: true} //データストアに即反映
+	instance, err := aetest.NewInstance(&opt)
+	if err != nil {
+		t.Fatalf("Failed to create aetest instance: %v", err)
+	}
+	defer instance.Close()
+
+	// 評価する値
+	expectedMid := "C00000000000000000000000000000000" //グループなので先頭は"C"
+	expectedHash := "3f7ffd73ba174332ae05bd363eba8e71"
+
+	// http.Requestを生成
+	param := url.Values{
+		"mid":        {expectedMid},
+		"type":       {"join"},
+		"replyToken": {"nHuyWiB7yP5Zw52FIkcQobQuGDXCTA"},
+	}
+	req, err := instance.NewRequest("POST", "/task/join", strings.NewReader(param.Encode()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded") //必須
+
+	// Contextとhttp.Clientは、テストコード側でインスタンス化する（モックと共通のインスタンスを使う必要があるため）
+	ctx := appengine.NewContext(req)
+	client := urlfetch.Client(ctx)
+
+	// LINEへのReply Messageリクエストをモックする
+	httpmock.ActivateNonDefault(client)
+	defer httpmock.DeactivateAndReset()
+
+	actualSendMessages := []string{} //モックに送られたリプライメッセージを保持し、後で検証する
+	httpmock.RegisterStubRequest(
+		httpmock.NewStubRequest(
+			"POST",
+			"https://api.line.me/v2/bot/message/reply",
+			func(req *http.Request) (*http.Response, error) {
+				defer req.Body.Close()
+				if body, err := ioutil.ReadAll(req.Body); err == nil {
+					actualSendMessages = append(actualSendMessages, string(body))
+					return httpmock.NewStringResponse(200, "{}"), nil
+				}
+				return httpmock.NewStringResponse(500, "Unread post body"), nil
+			},
+		),
+	)
+
+	// 1日前にBOTが削除された購読者エンティティ
+	entity := subscriber{
+		MID:            expectedMid,
+		DisplayName:    "既存のグループ",
+		ChouseisanHash: expectedHash,
+		RemindTime:     19,
+		LeftAt:         time.Now().Add(-24 * time.Hour),
+	}
+	key := datastore.NewKey(ctx, "Subscriber", expectedMid, 0, nil)
+	if _, err = datastore.Put(ctx, key, &entity); err != nil {
+		t.Fatal(err)
+	}
+
+	// execute
+	res := httptest.NewRecorder()
+	joinWithContext(ctx, client, res, req) //モックと同じhttp.Clientインスタンスを渡す
+
+	// 以前の設定が残り、削除日時が消えていること
+	var actualEntity subscriber
+	if err = datastore.Get(ctx, key, &actualEntity); err != nil {
+		t.Fatal(err)
+	}
+	if actualEntity.ChouseisanHash != expectedHash || actualEntity.RemindTime != 19 {
+		t.Errorf("Settings were not restored. hash='%v' remindTime='%v'", actualEntity.ChouseisanHash, actualEntity.RemindTime)
+	}
+	if !actualEntity.LeftAt.IsZero() {
+		t.Errorf("LeftAt was not cleared. leftAt='%v'", actualEntity.LeftAt)
+	}
+
+	// 引き継いだことをリプライすること
+	if len(actualSendMessages) != 1 || !strings.Contains(actualSendMessages[0], "以前の設定を引き継ぎました") {
+		t.Errorf("Unmatch reply messages: %v", actualSendMessages)
+	}
+}
+
 /**
  * 登録イベント：ユーザ
  */
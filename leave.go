@@ -13,7 +13,9 @@ import (
 )
 
 /**
- * 友だち削除（データストアから削除）
+ * 友だち削除（購読者に削除日時を記録する）
+ *
+ * 再招待されたときに設定を引き継げるよう、購読者エンティティは保持期間が過ぎるまで削除しない（purgeSubscribersで削除する）
  *
  * 引数にContextとhttp.Clientを取るインナーメソッド
  */
@@ -28,9 +30,10 @@ func leaveWithContext(c context.Context, client *http.Client, w http.ResponseWri
 		return
 	}
 
-	//購読者を削除
-	if err := datastore.Delete(c, key); err != nil {
-		log.Errorf(c, "Error occurred at delete subcriber to datastore. mid:%v, err: %v", mid, err)
+	//購読者に削除日時を記録
+	entity.LeftAt = time.Now()
+	if _, err := datastore.Put(c, key, &entity); err != nil {
+		log.Errorf(c, "Error occurred at put subcriber to datastore. mid:%v, err: %v", mid, err)
 		return
 	}
 
@@ -49,7 +52,7 @@ func leaveWithContext(c context.Context, client *http.Client, w http.ResponseWri
 }
 
 /**
- * 友だち削除（データストアから削除）
+ * 友だち削除（購読者に削除日時を記録する）
  */
 func leave(w http.ResponseWriter, r *http.Request) {
 	c := appengine.NewContext(r)
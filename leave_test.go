@@ -46,7 +46,7 @@ func TestLeaveGroup(t *testing.T) {
 	ctx := appengine.NewContext(req)
 	client := urlfetch.Client(ctx)
 
-	// 削除される購読者エンティティを用意しておく
+	// 削除日時が記録される購読者エンティティを用意しておく
 	entity := subscriber{
 		MID:         expectedMid,
 		DisplayName: expectedName,
@@ -71,8 +71,11 @@ func TestLeaveGroup(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	if len(subscribers) != 0 {
-		t.Fatal("Subscriber entity is not remove")
+	if len(subscribers) != 1 {
+		t.Fatal("Subscriber entity was removed")
+	}
+	if subscribers[0].LeftAt.IsZero() {
+		t.Errorf("Subscriber entity was not marked as left")
 	}
 
 	// データストアの内容を確認（ログエンティティ）
@@ -130,7 +133,7 @@ func TestLeaveUser(t *testing.T) {
 	httpmock.ActivateNonDefault(client)
 	defer httpmock.DeactivateAndReset()
 
-	// 削除される購読者エンティティを用意しておく
+	// 削除日時が記録される購読者エンティティを用意しておく
 	entity := subscriber{
 		MID:         expectedMid,
 		DisplayName: expectedName,
@@ -160,8 +163,11 @@ func TestLeaveUser(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	if len(subscribers) != 0 {
-		t.Errorf("Subscriber entity is not remove")
+	if len(subscribers) != 1 {
+		t.Fatal("Subscriber entity was removed")
+	}
+	if subscribers[0].LeftAt.IsZero() {
+		t.Errorf("Subscriber entity was not marked as left")
 	}
 
 	// データストアの内容を確認（ログエンティティ）
@@ -5,6 +5,8 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"strings"
+	"sync"
 	"time"
 
 	"golang.org/x/net/context"
@@ -19,11 +21,23 @@ import (
 
 // 購読者エンティティ（keyはMID）
 type subscriber struct {
-	DisplayName    string // 表示名（取得できるのはユーザの場合のみ）
-	MID            string // ユーザ/グループ/ルームのid
-	ChouseisanHash string // リマインド対象の調整さんのハッシュ
-	RemindBefore   int    // イベントの何日前にリマインド処理を行なうか。デフォルトは3日
-	RemindTime     int    // 何時にリマインド処理を行なうか（日本時間）。デフォルトは8:00
+	DisplayName      string    // 表示名（ユーザはプロフィール、グループはグループの概要から取得する。ルームはid）
+	MID              string    // ユーザ/グループ/ルームのid
+	ChouseisanHash   string    // リマインド対象の調整さんのハッシュ
+	RemindBefore     int       // イベントの何日前にリマインド処理を行なうか。デフォルトは3日
+	RemindTime       int       // 何時にリマインド処理を行なうか（TimeZoneの時刻）。デフォルトは8:00
+	QuietStart       int       // リマインドを送信しない時間帯の開始時刻（TimeZoneの時刻）
+	QuietEnd         int       // リマインドを送信しない時間帯の終了時刻（TimeZoneの時刻）。QuietStartと同じ値であれば時間帯なし
+	TimeZone         string    // イベント日程およびリマインド時刻を解釈するタイムゾーン（tz database名）。空の場合はAsia/Tokyo
+	HolidayPolicy    string    // 土日祝日のリマインド方針（send/skip/shift）。空の場合はsend
+	Deadline         time.Time // 出欠の回答期限（ゼロ値であれば期限なし）
+	DeadlineBefore   int       // 回答期限の何日前に未回答のメンバーをリマインドするか
+	NotifyChanges    bool      // リマインド後の出欠の変更を通知するか
+	ReminderTemplate string    `datastore:",noindex"` // リマインドのメッセージのテンプレート（text/template）。空の場合はFlex Messageのカード
+	Lang             string    // 返信およびリマインドの言語（ja/en）。空の場合はja
+	Onboarding       string    // 初期設定の段階（await_url: 調整さんのURL待ち）。空の場合は初期設定済み
+	LeftAt           time.Time // BOTがグループ/ルームから削除された（友だち解除された）日時。ゼロ値であれば参加中
+	NameOverridden   bool      // `/set name`で表示名を設定したか（trueであれば、表示名の定期的な更新で上書きしない）
 }
 
 // 購読者の追加・削除ログを保存するエンティティ
@@ -34,22 +48,55 @@ type logSubscriber struct {
 	AddTime     time.Time
 }
 
+// グループ/トークルームへのメンバーの参加、退出のログ
+type logMember struct {
+	MID       string // グループ/ルームのid
+	UID       string // LINEユーザのid
+	EventType string // memberJoined/memberLeft
+	AddTime   time.Time
+}
+
+// LINEユーザと調整さんのメンバー名を紐付けるエンティティ（keyはMIDとLINEユーザのidを"/"で連結したもの）
+type memberLink struct {
+	MID         string // グループ/ルーム（1:1の場合はユーザ）のid
+	UID         string // LINEユーザのid
+	Name        string // 調整さんのメンバー名
+	DisplayName string // LINEの表示名（紐付け時点）
+	Personal    bool   // 回答期限が近づいたら、未入力/△の日程を1:1トークで通知するか
+	AddTime     time.Time
+}
+
 func init() {
 	http.HandleFunc("/line/callback", lineCallback)
 	http.HandleFunc("/task/join", join)
 	http.HandleFunc("/task/leave", leave)
 	http.HandleFunc("/task/commandanalyze", commandAnalyze)
+	http.HandleFunc("/task/remind", remind)
+	http.HandleFunc("/task/postback", postback)
+	http.HandleFunc("/task/onboarding", onboarding)
+	http.HandleFunc("/task/member", member)
 	http.HandleFunc("/cron/crawlchouseisan", crawlChouseisan)
+	http.HandleFunc("/cron/purgesubscribers", purgeSubscribers)
+	http.HandleFunc("/cron/refreshnames", refreshNames)
+	http.HandleFunc("/admin/richmenu", richMenu)
+	http.HandleFunc("/chart", chart)
 	http.HandleFunc("/", usage)
 }
 
+// Messaging APIのエンドポイント（SDKのクライアントと、SDKを通さずに送るメッセージで共通）
+const lineEndpointBase = "https://api.line.me"
+
+/**
+ * Messaging APIのチャネルアクセストークンを返す
+ */
+func channelAccessToken() string {
+	return os.Getenv("LINE_CHANNEL_ACCESS_TOKEN")
+}
+
 func createBotClient(c context.Context, client *http.Client) (bot *linebot.Client, err error) {
-	var (
-		channelSecret = os.Getenv("LINE_CHANNEL_SECRET")
-		channelToken  = os.Getenv("LINE_CHANNEL_ACCESS_TOKEN")
-	)
+	channelSecret := os.Getenv("LINE_CHANNEL_SECRET")
 
-	bot, err = linebot.New(channelSecret, channelToken, linebot.WithHTTPClient(client)) //Appengineのurlfetchを使用する
+	bot, err = linebot.New(channelSecret, channelAccessToken(), linebot.WithHTTPClient(client), linebot.WithEndpointBase(lineEndpointBase)) //Appengineのurlfetchを使用する
 	if err != nil {
 		log.Errorf(c, "Error occurred at create linebot client: %v", err)
 		return bot, err
@@ -57,6 +104,29 @@ func createBotClient(c context.Context, client *http.Client) (bot *linebot.Clien
 	return bot, nil
 }
 
+// BOTのユーザID（メンションの判定に使う。取得できたらインスタンスが終わるまで使い回す）
+var (
+	botUserIDMutex  sync.Mutex
+	cachedBotUserID string
+)
+
+/**
+ * BOTのユーザIDを返す。取得できなければ空文字（メンションはコマンドとして受け付けない）
+ */
+func botUserID(c context.Context, bot *linebot.Client) string {
+	botUserIDMutex.Lock()
+	defer botUserIDMutex.Unlock()
+	if cachedBotUserID == "" {
+		info, err := bot.GetBotInfo().Do()
+		if err != nil {
+			log.Warningf(c, "Error occurred at get bot info. err:%v", err)
+			return ""
+		}
+		cachedBotUserID = info.UserID
+	}
+	return cachedBotUserID
+}
+
 /**
  * Get event sender's id
  */
@@ -113,15 +183,57 @@ func lineCallback(w http.ResponseWriter, r *http.Request) {
 			})
 			taskqueue.Add(c, task, "default")
 
+		case linebot.EventTypeMemberJoined, linebot.EventTypeMemberLeft:
+			// 参加/退出したメンバーのidは、複数の`uid`で渡す
+			var members []linebot.EventSource
+			if event.Joined != nil {
+				members = event.Joined.Members
+			} else if event.Left != nil {
+				members = event.Left.Members
+			}
+			uids := []string{}
+			for _, m := range members {
+				uids = append(uids, m.UserID)
+			}
+			task := taskqueue.NewPOSTTask("/task/member", url.Values{
+				"mid":        {getSenderID(c, event)},
+				"type":       {string(event.Type)},
+				"replyToken": {event.ReplyToken},
+				"uid":        uids,
+			})
+			taskqueue.Add(c, task, "default")
+
+		case linebot.EventTypePostback:
+			task := taskqueue.NewPOSTTask("/task/postback", url.Values{
+				"mid":        {getSenderID(c, event)},
+				"uid":        {event.Source.UserID},
+				"replyToken": {event.ReplyToken},
+				"data":       {event.Postback.Data},
+			})
+			taskqueue.Add(c, task, "default")
+
 		case linebot.EventTypeMessage:
 			switch message := event.Message.(type) {
 			case *linebot.TextMessage:
-				if message.Text[0:1] == "/" {
+				// BOTへのメンションはメンション先のユーザIDで判定し、メンションを除いたテキストを扱う
+				mentioned, text := false, message.Text
+				if message.Mention != nil {
+					mentioned, text = trimBotMention(message.Text, message.Mention.Mentionees, botUserID(c, bot))
+				}
+				if b, command := parseCommand(text, mentioned); b {
 					task := taskqueue.NewPOSTTask("/task/commandanalyze", url.Values{
 						"mid":        {getSenderID(c, event)},
 						"uid":        {event.Source.UserID},
 						"replyToken": {event.ReplyToken},
-						"text":       {message.Text[1:]},
+						"text":       {command},
+					})
+					taskqueue.Add(c, task, "default")
+				} else if strings.Contains(text, "chouseisan.com") || containsLineShortURL(text) {
+					// 調整さんのURLか、LINEが短縮したURLを含むメッセージだけをタスクにする（初期設定中はそのまま受け付け、それ以外は設定するか確認する）
+					task := taskqueue.NewPOSTTask("/task/onboarding", url.Values{
+						"mid":        {getSenderID(c, event)},
+						"replyToken": {event.ReplyToken},
+						"text":       {strings.TrimSpace(text)},
 					})
 					taskqueue.Add(c, task, "default")
 				}
@@ -137,10 +249,18 @@ func lineCallback(w http.ResponseWriter, r *http.Request) {
  * Usageを表示
  */
 func usage(w http.ResponseWriter, r *http.Request) {
-	response := template.Must(template.ParseFiles("templates/usage.html"))
+	// 言語はクエリパラメータ`lang`で指定、なければAccept-Languageヘッダから選ぶ
+	lang := r.FormValue("lang")
+	if !isSupportedLang(lang) {
+		lang = acceptLang(r.Header.Get("Accept-Language"))
+	}
+
+	response := template.Must(template.New("usage.html").Funcs(usageFuncs(lang)).ParseFiles("templates/usage.html"))
 	response.Execute(w, struct {
 		Version string //バージョン番号
+		Lang    string //表示する言語
 	}{
 		Version: version, // `make version`で生成されるversion.goに定義
+		Lang:    lang,
 	})
 }
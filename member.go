@@ -0,0 +1,112 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/line/line-bot-sdk-go/linebot"
+
+	"google.golang.org/appengine"
+	"google.golang.org/appengine/datastore"
+	"google.golang.org/appengine/log"
+	"google.golang.org/appengine/urlfetch"
+)
+
+/**
+ * メンバーの参加、退出のログを追加する
+ */
+func writeLogMembers(c context.Context, mid string, uids []string, eventType string) {
+	for _, uid := range uids {
+		logEntity := logMember{
+			MID:       mid,
+			UID:       uid,
+			EventType: eventType,
+			AddTime:   time.Now(),
+		}
+		logKey := datastore.NewIncompleteKey(c, "LogMember", nil)
+		if _, err := datastore.Put(c, logKey, &logEntity); err != nil {
+			log.Errorf(c, "Error occurred at put log-member to datastore. mid:%v uid:%v err:%v", mid, uid, err)
+		}
+	}
+}
+
+/**
+ * 退出したメンバーの紐付けを削除して、紐付いていた調整さんのメンバー名を返す
+ */
+func unlinkLeftMembers(c context.Context, mid string, uids []string) []string {
+	names := []string{}
+	for _, uid := range uids {
+		name, err := readMemberName(c, mid, uid)
+		if err != nil || name == "" {
+			continue
+		}
+		if err := datastore.Delete(c, memberLinkKey(c, mid, uid)); err != nil {
+			log.Errorf(c, "Error occurred at delete MemberLink entity. mid:%v uid:%v err:%v", mid, uid, err)
+			continue
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+/**
+ * メンバーが退出したときに、調整さんの出欠表から削除するよう促すメッセージを組み立てて返す
+ */
+func constructMemberLeftMessage(lang string, names []string, hash string) string {
+	return msg(lang, "member.left", strings.Join(names, ", "), "https://chouseisan.com/s?h="+hash)
+}
+
+/**
+ * グループ/トークルームへのメンバーの参加、退出を記録する
+ *
+ * 参加したメンバーには、調整さんイベントのURLをリプライする
+ * 退出したメンバーは紐付けを削除し、調整さんの出欠表から削除するようプッシュで促す（退出イベントにはリプライトークンがないため。紐付いていないメンバーは名前が分からないので送信しない）
+ *
+ * 引数にContextとhttp.Clientを取るインナーメソッド
+ */
+func memberWithContext(c context.Context, client *http.Client, w http.ResponseWriter, r *http.Request) {
+	mid := r.FormValue("mid")
+	eventType := r.FormValue("type")
+	r.ParseForm()
+	uids := r.Form["uid"]
+
+	writeLogMembers(c, mid, uids, eventType)
+
+	entity, err := readSubscriber(c, mid)
+	if err != nil || !entity.LeftAt.IsZero() {
+		return
+	}
+	lang := entity.lang()
+
+	switch eventType {
+	case string(linebot.EventTypeMemberJoined):
+		if entity.ChouseisanHash == "" {
+			return
+		}
+		replyMessage(c, client, r.FormValue("replyToken"), msg(lang, "member.joined", "https://chouseisan.com/s?h="+entity.ChouseisanHash))
+
+	case string(linebot.EventTypeMemberLeft):
+		names := unlinkLeftMembers(c, mid, uids)
+		if len(names) == 0 || entity.ChouseisanHash == "" {
+			return
+		}
+		bot, err := createBotClient(c, client)
+		if err != nil {
+			return
+		}
+		if err := sendPush(bot, mid, newTextMessages(constructMemberLeftMessage(lang, names, entity.ChouseisanHash))); err != nil {
+			log.Errorf(c, "Error occurred at push member-left message. mid:%v err:%v", mid, err)
+		}
+	}
+}
+
+/**
+ * グループ/トークルームへのメンバーの参加、退出を記録する
+ */
+func member(w http.ResponseWriter, r *http.Request) {
+	c := appengine.NewContext(r)
+	memberWithContext(c, urlfetch.Client(c), w, r)
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/thingful/httpmock"
+	"google.golang.org/appengine"
+	"google.golang.org/appengine/aetest"
+	"google.golang.org/appengine/datastore"
+	"google.golang.org/appengine/urlfetch"
+)
+
+/**
+ * メンバーが退出したときのメッセージ
+ */
+func TestConstructMemberLeftMessage(t *testing.T) {
+	expected := "電一, 電二 さんがグループを退出しました。調整さんの出欠表から削除する場合は、調整さんのページで編集してください\n" +
+		"https://chouseisan.com/s?h=3f7ffd73ba174332ae05bd363eba8e71"
+	if actual := constructMemberLeftMessage(langJa, []string{"電一", "電二"}, "3f7ffd73ba174332ae05bd363eba8e71"); actual != expected {
+		t.Errorf("Unmatch message\nexpect:\n%v\nactual:\n%v", expected, actual)
+	}
+}
+
+/**
+ * メンバーの参加、退出の記録と、参加/退出したメンバーについてのメッセージ
+ */
+func TestMemberWithContext(t *testing.T) {
+	opt := aetest.Options{StronglyConsistentDatastore: true} //データストアに即反映
+	instance, err := aetest.NewInstance(&opt)
+	if err != nil {
+		t.Fatalf("Failed to create aetest instance: %v", err)
+	}
+	defer instance.Close()
+
+	hash := "3f7ffd73ba174332ae05bd363eba8e71"
+	mid := "C00000000000000000000000000000000"
+
+	type testParameter struct {
+		eventType      string
+		uids           []string
+		expectedPath   string // メッセージを送るAPI（送らない場合は空文字）
+		expectedText   string // メッセージに含まれるテキスト
+		expectedLinks  int    // 処理後に残っているメンバーの紐付けの数
+		expectedLogNum int    // 処理後のログの数
+	}
+	testCases := []testParameter{{
+		eventType:      "memberJoined",
+		uids:           []string{"U00000000000000000000000000000003"},
+		expectedPath:   "https://api.line.me/v2/bot/message/reply",
+		expectedText:   "https://chouseisan.com/s?h=" + hash,
+		expectedLinks:  2,
+		expectedLogNum: 1,
+	}, {
+		eventType:      "memberLeft",
+		uids:           []string{"U00000000000000000000000000000001", "U00000000000000000000000000000003"}, // 紐付いていないメンバーは名前に含めない
+		expectedPath:   "https://api.line.me/v2/bot/message/push",
+		expectedText:   "電一 さんがグループを退出しました",
+		expectedLinks:  1,
+		expectedLogNum: 3,
+	}, {
+		eventType:      "memberLeft",
+		uids:           []string{"U00000000000000000000000000000003"}, // 紐付いていないメンバーだけなら送信しない
+		expectedPath:   "",
+		expectedText:   "",
+		expectedLinks:  1,
+		expectedLogNum: 4,
+	}}
+
+	for _, current := range testCases {
+		form := url.Values{
+			"mid":        {mid},
+			"type":       {current.eventType},
+			"replyToken": {"00000000000000000000000000000000"},
+			"uid":        current.uids,
+		}
+		req, err := instance.NewRequest("POST", "/task/member", strings.NewReader(form.Encode()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded") //必須
+
+		// Contextとhttp.Clientは、テストコード側でインスタンス化する（モックと共通のインスタンスを使う必要があるため）
+		ctx := appengine.NewContext(req)
+		client := urlfetch.Client(ctx)
+
+		// 購読者エンティティと、メンバーの紐付けを用意しておく
+		if current.eventType == "memberJoined" {
+			entity := subscriber{MID: mid, ChouseisanHash: hash}
+			if _, err = datastore.Put(ctx, datastore.NewKey(ctx, "Subscriber", mid, 0, nil), &entity); err != nil {
+				t.Fatal(err)
+			}
+			if err := writeMemberLink(ctx, mid, "U00000000000000000000000000000001", "電一", "でんいち"); err != nil {
+				t.Fatal(err)
+			}
+			if err := writeMemberLink(ctx, mid, "U00000000000000000000000000000002", "電二", "でんじ"); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		// LINEへのリクエストをモックする
+		httpmock.ActivateNonDefault(client)
+		actualSendMessages := map[string][]string{} //モックに送られたメッセージをAPIごとに保持し、後で検証する
+		for _, path := range []string{"https://api.line.me/v2/bot/message/reply", "https://api.line.me/v2/bot/message/push"} {
+			path := path
+			httpmock.RegisterStubRequest(
+				httpmock.NewStubRequest(
+					"POST",
+					path,
+					func(req *http.Request) (*http.Response, error) {
+						defer req.Body.Close()
+						if body, err := ioutil.ReadAll(req.Body); err == nil {
+							actualSendMessages[path] = append(actualSendMessages[path], string(body))
+							return httpmock.NewStringResponse(200, "{}"), nil
+						}
+						return httpmock.NewStringResponse(500, "Unread post body"), nil
+					},
+				),
+			)
+		}
+
+		// execute
+		res := httptest.NewRecorder()
+		memberWithContext(ctx, client, res, req) //モックと同じhttp.Clientインスタンスを渡す
+		httpmock.DeactivateAndReset()
+
+		// 期待したAPIにだけメッセージを送っていること
+		if current.expectedPath == "" {
+			if len(actualSendMessages) != 0 {
+				t.Errorf("Unexpected messages. type:%v messages:%v", current.eventType, actualSendMessages)
+			}
+		} else if len(actualSendMessages) != 1 || len(actualSendMessages[current.expectedPath]) != 1 ||
+			!strings.Contains(actualSendMessages[current.expectedPath][0], current.expectedText) {
+			t.Errorf("Unmatch messages. type:%v messages:%v", current.eventType, actualSendMessages)
+		}
+
+		// 退出したメンバーの紐付けが削除され、ログが追加されていること
+		links, err := queryMemberLinks(ctx, mid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(links) != current.expectedLinks {
+			t.Errorf("Unmatch number of member links. type:%v links:%v", current.eventType, links)
+		}
+		logs := []logMember{}
+		if _, err = datastore.NewQuery("LogMember").GetAll(ctx, &logs); err != nil {
+			t.Fatal(err)
+		}
+		if len(logs) != current.expectedLogNum {
+			t.Errorf("Unmatch number of log members. type:%v logs:%v", current.eventType, len(logs))
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"google.golang.org/appengine/log"
+)
+
+// 1メッセージでメンションするユーザの最大数（超えた分は名前のみ列挙する）
+const maxMentionees = 20
+
+// メンションの置換（textV2のsubstitution）
+type mentionSubstitution struct {
+	Type      string `json:"type"`
+	Mentionee struct {
+		Type   string `json:"type"`
+		UserID string `json:"userId"`
+	} `json:"mentionee"`
+}
+
+// メンションを含むテキストメッセージ（textV2）
+//
+// SDKのTextMessageはメンションを送信できないため、Messaging APIのJSONを直接組み立てる
+type mentionMessage struct {
+	Type         string                         `json:"type"`
+	Text         string                         `json:"text"`
+	Substitution map[string]mentionSubstitution `json:"substitution,omitempty"`
+}
+
+/**
+ * 出欠が未入力のメンバーへ入力を促すメッセージを組み立てて返す
+ *
+ * LINEユーザと紐付いているメンバーはメンション、紐付いていないメンバーは名前のみ列挙する
+ */
+func constructMentionMessage(lang string, dateString string, names []string, links map[string]string) mentionMessage {
+	message := mentionMessage{Type: "textV2", Substitution: map[string]mentionSubstitution{}}
+
+	mentions := []string{}
+	plain := []string{}
+	for _, name := range names {
+		if uid, exist := links[name]; exist && len(message.Substitution) < maxMentionees {
+			key := "m" + strconv.Itoa(len(message.Substitution))
+			s := mentionSubstitution{Type: "mention"}
+			s.Mentionee.Type = "user"
+			s.Mentionee.UserID = uid
+			message.Substitution[key] = s
+			mentions = append(mentions, "{"+key+"}")
+		} else {
+			// textV2では"{"、"}"が置換の記号になるため、名前に含まれる場合はエスケープする
+			plain = append(plain, strings.NewReplacer("{", "{{", "}", "}}").Replace(name))
+		}
+	}
+
+	text := msg(lang, "mention.unanswered", strings.NewReplacer("{", "{{", "}", "}}").Replace(dateString)) + "\n" + strings.Join(mentions, " ")
+	if len(plain) > 0 {
+		text += "\n" + msg(lang, "mention.names", truncateNames(plain, ",", maxNamesLength))
+	}
+	message.Text = text + "\n" + msg(lang, "mention.request")
+	return message
+}
+
+/**
+ * textV2のテキストを、置換の記号（"{m0}"）やエスケープ（"{{"、"}}"）を途中で切らずに、上限の文字数に収まるよう末尾を"…"にして切り詰めて返す
+ */
+func truncateTextV2(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+
+	end := 0 // 記号を切らずに切り詰められる位置
+	for i := 0; i < len(runes); {
+		next := i + 1
+		switch {
+		case (runes[i] == '{' || runes[i] == '}') && next < len(runes) && runes[next] == runes[i]:
+			next++
+		case runes[i] == '{':
+			for next < len(runes) && runes[next] != '}' {
+				next++
+			}
+			next++
+		}
+		if next > limit-1 { // "…"の分を残す
+			break
+		}
+		i, end = next, next
+	}
+	return string(runes[:end]) + "…"
+}
+
+/**
+ * テキストを上限の文字数に収めたメッセージを返す。切り詰めてテキストからなくなった置換は除く
+ */
+func (m mentionMessage) truncated(limit int) mentionMessage {
+	text := truncateTextV2(m.Text, limit)
+	if text == m.Text {
+		return m
+	}
+	truncated := mentionMessage{Type: m.Type, Text: text, Substitution: map[string]mentionSubstitution{}}
+	for key, v := range m.Substitution {
+		if strings.Contains(text, "{"+key+"}") {
+			truncated.Substitution[key] = v
+		}
+	}
+	return truncated
+}
+
+/**
+ * メンションを含むメッセージをプッシュする（テキストは上限の文字数に切り詰める）
+ */
+func pushMentionMessage(c context.Context, client *http.Client, to string, message mentionMessage) error {
+	return pushJSONMessages(c, client, to, []interface{}{message.truncated(maxTextLength)})
+}
+
+/**
+ * グループ/トークルームで、出欠が未入力のメンバーをメンションして入力を促す
+ *
+ * LINEユーザと紐付いているメンバーがいなければ送信しない（未入力のメンバーの名前はリマインドのカードに表示済みのため）
+ */
+func mentionUnanswered(c context.Context, client *http.Client, current *subscriber, s *schedule) {
+	if len(current.MID) == 0 || current.MID[0:1] == "U" {
+		return
+	}
+	names := s.namesByAnswer("")
+	if len(names) == 0 {
+		return
+	}
+
+	links, err := readMemberLinks(c, current.MID)
+	if err != nil {
+		return
+	}
+	linked := false
+	for _, name := range names {
+		if _, exist := links[name]; exist {
+			linked = true
+			break
+		}
+	}
+	if !linked {
+		return
+	}
+
+	log.Infof(c, "Mention unanswered members! subscriber:%v date:%v", current.DisplayName, s.DateString)
+	if err := pushMentionMessage(c, client, current.MID, constructMentionMessage(current.lang(), s.DateString, names, links)); err != nil {
+		log.Errorf(c, "Error occurred at mention unanswered members. subscriber:%v, date:%v, err: %v", current.DisplayName, s.DateString, err)
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import "testing"
+
+/**
+ * 出欠が未入力のメンバーへのメンションの組み立て（紐付いていないメンバーは名前のみ）
+ */
+func TestConstructMentionMessage(t *testing.T) {
+	links := map[string]string{
+		"電次郎": "U00000000000000000000000000000002",
+		"電五郎": "U00000000000000000000000000000005",
+	}
+	actual := constructMentionMessage(langJa, "12/24(土)", []string{"電一", "電次郎", "電五郎", "{電六郎}"}, links)
+
+	expectedText := "12/24(土)の出欠がまだ入力されていません\n{m0} {m1}\n電一,{{電六郎}}さん\n出欠の入力をお願いします"
+	if actual.Type != "textV2" || actual.Text != expectedText {
+		t.Errorf("Unmatch mention message\nexpect:\n%v\nactual:\n%v", expectedText, actual.Text)
+	}
+	if len(actual.Substitution) != 2 {
+		t.Fatalf("Unmatch substitution count: %v", len(actual.Substitution))
+	}
+	if uid := actual.Substitution["m1"].Mentionee.UserID; uid != "U00000000000000000000000000000005" {
+		t.Errorf("Unmatch mentionee: %v", uid)
+	}
+
+	// 英語のグループ
+	actual = constructMentionMessage(langEn, "12/24(土)", []string{"電一", "電次郎", "電五郎", "{電六郎}"}, links)
+	expectedText = "Attendance for 12/24(土) has not been entered yet\n{m0} {m1}\n電一,{{電六郎}}\nPlease enter your attendance"
+	if actual.Text != expectedText {
+		t.Errorf("Unmatch mention message\nexpect:\n%v\nactual:\n%v", expectedText, actual.Text)
+	}
+}
+
+/**
+ * textV2のテキストの切り詰め（置換の記号やエスケープを途中で切らない）
+ */
+func TestTruncateTextV2(t *testing.T) {
+	type testParameter struct {
+		text     string
+		limit    int
+		expected string
+	}
+	testCases := []testParameter{{
+		text:     "出欠 {m0} {m1}",
+		limit:    20, // 上限以内ならそのまま
+		expected: "出欠 {m0} {m1}",
+	}, {
+		text:     "出欠 {m0} {m1}",
+		limit:    10, // "{m1}"の途中で切れる場合は、記号の前まで
+		expected: "出欠 {m0} …",
+	}, {
+		text:     "出欠 {{電六郎}}さん",
+		limit:    8, // エスケープの途中で切れる場合は、エスケープの前まで
+		expected: "出欠 {{電六…",
+	}, {
+		text:     "出欠 {{電六郎}}さん",
+		limit:    4,
+		expected: "出欠 …",
+	}}
+
+	for _, current := range testCases {
+		if actual := truncateTextV2(current.text, current.limit); actual != current.expected {
+			t.Errorf("Illegal return value. text:%v, limit:%v, returnd:%v", current.text, current.limit, actual)
+		}
+	}
+}
+
+/**
+ * 上限の文字数に収めたメンションのメッセージ（テキストからなくなった置換は除く）
+ */
+func TestMentionMessageTruncated(t *testing.T) {
+	links := map[string]string{
+		"電次郎": "U00000000000000000000000000000002",
+		"電五郎": "U00000000000000000000000000000005",
+	}
+	message := constructMentionMessage(langJa, "12/24(土)", []string{"電次郎", "電五郎"}, links)
+
+	actual := message.truncated(30)
+	if _, exist := actual.Substitution["m0"]; !exist || len(actual.Substitution) != 1 {
+		t.Errorf("Unmatch substitution. text:%v substitution:%v", actual.Text, actual.Substitution)
+	}
+	if actual := message.truncated(maxTextLength); actual.Text != message.Text || len(actual.Substitution) != 2 {
+		t.Errorf("Message within the limit was changed. text:%v", actual.Text)
+	}
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/context"
+
+	"github.com/line/line-bot-sdk-go/linebot"
+
+	"google.golang.org/appengine/log"
+)
+
+// LINE Messaging APIの制限など、送信メッセージの長さの上限
+const (
+	maxTextLength         = 5000 // テキストメッセージの最大文字数
+	maxAltTextLength      = 400  // テンプレート、Flex Messageの代替テキストの最大文字数
+	maxTemplateTextLength = 160  // ボタンテンプレートのテキストの最大文字数（画像、タイトルなしの場合）
+	maxMessagesPerRequest = 5    // 1回のリプライ、プッシュで送信できるメッセージ数
+	maxQuickReplyItems    = 13   // クイックリプライのボタンの最大数
+	maxQuickReplyLabel    = 20   // クイックリプライのボタンのラベルの最大文字数
+	maxNamesLength        = 300  // 名前を列挙する際の最大文字数（超えた分は「他N名」と省略）
+)
+
+/**
+ * 文字数（rune数）が上限を超えていれば、末尾を"…"にして切り詰めて返す
+ */
+func truncateText(text string, limit int) string {
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+	runes := []rune(text)
+	return string(runes[:limit-1]) + "…"
+}
+
+/**
+ * 名前を区切り文字で列挙して返す。上限の文字数を超える場合は、入りきらない名前を「他N名」と省略する
+ */
+func truncateNames(names []string, sep string, limit int) string {
+	reserve := utf8.RuneCountInString("他" + strconv.Itoa(len(names)) + "名")
+	result := ""
+	for i, name := range names {
+		candidate := name
+		if i > 0 {
+			candidate = result + sep + name
+		}
+		available := limit
+		if i < len(names)-1 {
+			available -= reserve //後続の名前を省略する場合に備える
+		}
+		if utf8.RuneCountInString(candidate) > available {
+			return result + "他" + strconv.Itoa(len(names)-i) + "名"
+		}
+		result = candidate
+	}
+	return result
+}
+
+/**
+ * テキストを上限の文字数ごとに分割して返す。なるべく改行の位置で分割する
+ */
+func splitText(text string, limit int) []string {
+	chunks := []string{}
+	runes := []rune(text)
+	for len(runes) > limit {
+		cut := limit
+		head := string(runes[:limit+1]) //上限の直後が改行であれば、そこで分割できるように1文字多く見る
+		if i := strings.LastIndex(head, "\n"); i > 0 {
+			cut = utf8.RuneCountInString(head[:i]) + 1 //改行は前のチャンクに含めて、取り除く
+		}
+		chunks = appendChunk(chunks, string(runes[:cut]))
+		runes = runes[cut:]
+	}
+	return appendChunk(chunks, string(runes))
+}
+
+/**
+ * チャンクの前後の改行を取り除いて追加する。空白だけのチャンクは、LINEに送信できないので追加しない
+ */
+func appendChunk(chunks []string, chunk string) []string {
+	chunk = strings.Trim(chunk, "\n")
+	if strings.TrimSpace(chunk) == "" {
+		return chunks
+	}
+	return append(chunks, chunk)
+}
+
+/**
+ * テキストを、LINEの制限内に収まるテキストメッセージに分割して返す
+ *
+ * 1回で送信できるメッセージ数を超える分は、最後のメッセージの末尾を"…"にして切り捨てる
+ */
+func newTextMessages(text string) []linebot.SendingMessage {
+	chunks := splitText(text, maxTextLength)
+	if len(chunks) > maxMessagesPerRequest {
+		chunks = chunks[:maxMessagesPerRequest]
+		chunks[maxMessagesPerRequest-1] = truncateText(chunks[maxMessagesPerRequest-1]+"…", maxTextLength)
+	}
+
+	messages := []linebot.SendingMessage{}
+	for _, chunk := range chunks {
+		messages = append(messages, linebot.NewTextMessage(chunk))
+	}
+	return messages
+}
+
+/**
+ * 最後のメッセージ（テキストメッセージのみ）にクイックリプライを付けて返す。ボタンの最大数を超える分は付けない
+ */
+func withQuickReplies(messages []linebot.SendingMessage, buttons []*linebot.QuickReplyButton) []linebot.SendingMessage {
+	if len(messages) == 0 || len(buttons) == 0 {
+		return messages
+	}
+	last, ok := messages[len(messages)-1].(*linebot.TextMessage)
+	if !ok {
+		return messages
+	}
+	if len(buttons) > maxQuickReplyItems {
+		buttons = buttons[:maxQuickReplyItems]
+	}
+	messages[len(messages)-1] = last.WithQuickReplies(linebot.NewQuickReplyItems(buttons...))
+	return messages
+}
+
+/**
+ * メッセージをリプライする。1回で送信できるメッセージ数を超える分は送信しない（リプライトークンは1回しか使えないため）
+ */
+func sendReply(bot *linebot.Client, token string, messages []linebot.SendingMessage) error {
+	if len(messages) == 0 {
+		return nil
+	}
+	if len(messages) > maxMessagesPerRequest {
+		messages = messages[:maxMessagesPerRequest]
+	}
+	_, err := bot.ReplyMessage(token, messages...).Do()
+	return err
+}
+
+/**
+ * メッセージをプッシュする。1回で送信できるメッセージ数を超える場合は、複数回に分けて送信する
+ */
+func sendPush(bot *linebot.Client, to string, messages []linebot.SendingMessage) error {
+	for len(messages) > 0 {
+		n := len(messages)
+		if n > maxMessagesPerRequest {
+			n = maxMessagesPerRequest
+		}
+		if _, err := bot.PushMessage(to, messages[:n]...).Do(); err != nil {
+			return err
+		}
+		messages = messages[n:]
+	}
+	return nil
+}
+
+/**
+ * SDKが対応していないメッセージ（メンションを含むtextV2など）を、Messaging APIのJSONで直接プッシュする
+ *
+ * エンドポイントとチャネルアクセストークンは、SDKのクライアントと同じものを使う。1回で送信できるメッセージ数を超える場合は、複数回に分けて送信する
+ */
+func pushJSONMessages(c context.Context, client *http.Client, to string, messages []interface{}) error {
+	for len(messages) > 0 {
+		n := len(messages)
+		if n > maxMessagesPerRequest {
+			n = maxMessagesPerRequest
+		}
+		body, err := json.Marshal(struct {
+			To       string        `json:"to"`
+			Messages []interface{} `json:"messages"`
+		}{to, messages[:n]})
+		if err != nil {
+			return err
+		}
+
+		req, err := http.NewRequest("POST", lineEndpointBase+"/v2/bot/message/push", bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+		req.Header.Set("Authorization", "Bearer "+channelAccessToken())
+
+		res, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		res.Body.Close()
+		if res.StatusCode != 200 {
+			log.Errorf(c, "Push message failed. StatusCode: %v", res.StatusCode)
+			return errors.New("push message failed: " + res.Status)
+		}
+		messages = messages[n:]
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/line/line-bot-sdk-go/linebot"
+)
+
+/**
+ * 上限の文字数での切り詰め
+ */
+func TestTruncateText(t *testing.T) {
+	type testParameter struct {
+		text     string
+		limit    int
+		expected string
+	}
+	testCases := []testParameter{{
+		text:     "出欠状況をお知らせします",
+		limit:    20,
+		expected: "出欠状況をお知らせします",
+	}, {
+		text:     "出欠状況をお知らせします", // 文字数（バイト数でなく）で切り詰める
+		limit:    5,
+		expected: "出欠状況…",
+	}}
+
+	for _, current := range testCases {
+		actual := truncateText(current.text, current.limit)
+		if actual != current.expected {
+			t.Errorf("Illegal return value. text:%v, limit:%v, returnd:%v", current.text, current.limit, actual)
+		}
+	}
+}
+
+/**
+ * 名前の列挙と「他N名」での省略
+ */
+func TestTruncateNames(t *testing.T) {
+	type testParameter struct {
+		names    []string
+		limit    int
+		expected string
+	}
+	testCases := []testParameter{{
+		names:    []string{"電一", "電次郎", "電三太郎"},
+		limit:    20,
+		expected: "電一,電次郎,電三太郎",
+	}, {
+		names:    []string{"電一", "電次郎", "電三太郎"}, // ちょうど上限に収まる
+		limit:    11,
+		expected: "電一,電次郎,電三太郎",
+	}, {
+		names:    []string{"電一", "電次郎", "電三太郎", "電四郎"},
+		limit:    10,
+		expected: "電一,電次郎他2名",
+	}, {
+		names:    []string{"電三太郎"}, // ひとりも入らない
+		limit:    2,
+		expected: "他1名",
+	}, {
+		names:    []string{},
+		limit:    10,
+		expected: "",
+	}}
+
+	for _, current := range testCases {
+		actual := truncateNames(current.names, ",", current.limit)
+		if actual != current.expected {
+			t.Errorf("Illegal return value. names:%v, limit:%v, returnd:%v", current.names, current.limit, actual)
+		}
+	}
+}
+
+/**
+ * 上限の文字数ごとの分割（なるべく改行の位置で分割する）
+ */
+func TestSplitText(t *testing.T) {
+	actual := splitText("参加: 4名\n不参加: 1名\n未入力: 2名", 16)
+	expected := []string{"参加: 4名\n不参加: 1名", "未入力: 2名"}
+	if strings.Join(actual, "|") != strings.Join(expected, "|") {
+		t.Errorf("Unmatch split text: %v", actual)
+	}
+
+	// 改行がなければ上限の文字数で分割
+	actual = splitText(strings.Repeat("電", 25), 10)
+	if len(actual) != 3 || utf8.RuneCountInString(actual[0]) != 10 || utf8.RuneCountInString(actual[2]) != 5 {
+		t.Errorf("Unmatch split text: %v", actual)
+	}
+
+	// 改行が続く部分は、空のチャンクにしない
+	actual = splitText("参加: 4名"+strings.Repeat("\n", 30)+"未入力: 2名\n \n", 10)
+	expected = []string{"参加: 4名", "未入力: 2名"}
+	if strings.Join(actual, "|") != strings.Join(expected, "|") {
+		t.Errorf("Unmatch split text (newlines): %q", actual)
+	}
+}
+
+/**
+ * テキストメッセージへの分割（1回で送信できるメッセージ数まで）
+ */
+func TestNewTextMessages(t *testing.T) {
+	if actual := newTextMessages("無効なコマンドです。"); len(actual) != 1 {
+		t.Errorf("Unmatch messages count: %v", len(actual))
+	}
+
+	if actual := newTextMessages(""); len(actual) != 0 {
+		t.Errorf("Unmatch messages count (empty text): %v", len(actual))
+	}
+
+	actual := newTextMessages(strings.Repeat("電", maxTextLength*maxMessagesPerRequest+1))
+	if len(actual) != maxMessagesPerRequest {
+		t.Fatalf("Unmatch messages count (too long text): %v", len(actual))
+	}
+	last := actual[maxMessagesPerRequest-1].(*linebot.TextMessage).Text
+	if utf8.RuneCountInString(last) != maxTextLength || !strings.HasSuffix(last, "…") {
+		t.Errorf("Last message was not truncated. length:%v", utf8.RuneCountInString(last))
+	}
+}
+
+/**
+ * 最後のテキストメッセージへのクイックリプライの付与（ボタンの最大数まで）
+ */
+func TestWithQuickReplies(t *testing.T) {
+	buttons := []*linebot.QuickReplyButton{}
+	for i := 0; i < maxQuickReplyItems+1; i++ {
+		buttons = append(buttons, linebot.NewQuickReplyButton("", linebot.NewPostbackAction("8:00", commandPostbackData("set remindtime 8"), "", "")))
+	}
+
+	actual := withQuickReplies(newTextMessages("リマインダを登録しました！"), buttons)
+	if len(actual) != 1 {
+		t.Fatalf("Unmatch messages count: %v", len(actual))
+	}
+	body, err := json.Marshal(actual[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var message struct {
+		QuickReply struct {
+			Items []json.RawMessage `json:"items"`
+		} `json:"quickReply"`
+	}
+	if err = json.Unmarshal(body, &message); err != nil {
+		t.Fatal(err)
+	}
+	if len(message.QuickReply.Items) != maxQuickReplyItems {
+		t.Errorf("Unmatch quick reply items count: %v", len(message.QuickReply.Items))
+	}
+}
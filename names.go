@@ -0,0 +1,67 @@
+package main
+
+import (
+	"net/http"
+
+	"golang.org/x/net/context"
+
+	"google.golang.org/appengine"
+	"google.golang.org/appengine/datastore"
+	"google.golang.org/appengine/log"
+	"google.golang.org/appengine/urlfetch"
+)
+
+/**
+ * 表示名を定期的に更新する対象であればtrueを返す
+ *
+ * BOTが削除された購読者、`/set name`で表示名を設定した購読者、名前を取得できないルームは対象外
+ */
+func (s *subscriber) isNameRefreshable() bool {
+	return s.LeftAt.IsZero() && !s.NameOverridden && len(s.MID) > 0 && s.MID[0:1] != "R"
+}
+
+/**
+ * 購読者の表示名を、LINEのユーザ名もしくはグループ名で更新する
+ *
+ * 引数にContextとhttp.Clientを取るインナーメソッド
+ */
+func refreshNamesWithContext(c context.Context, client *http.Client, w http.ResponseWriter, r *http.Request) {
+	bot, err := createBotClient(c, client)
+	if err != nil {
+		return
+	}
+
+	ite := datastore.NewQuery("Subscriber").Run(c)
+	for {
+		var entity subscriber
+		key, err := ite.Next(&entity)
+		if err == datastore.Done {
+			break
+		} else if err != nil {
+			log.Errorf(c, "Error occurred at fetch Subscriber. err:%v", err)
+			break
+		}
+		if !entity.isNameRefreshable() {
+			continue
+		}
+
+		// 取得に失敗した場合（idが返ってくる）は、これまでの表示名を残す
+		name := getSenderName(c, bot, entity.MID)
+		if name == entity.MID || name == entity.DisplayName {
+			continue
+		}
+		log.Infof(c, "Refresh display name. mid:%v name:%v -> %v", entity.MID, entity.DisplayName, name)
+		entity.DisplayName = name
+		if _, err := datastore.Put(c, key, &entity); err != nil {
+			log.Errorf(c, "Error occurred at put Subscriber entity. mid:%v err:%v", entity.MID, err)
+		}
+	}
+}
+
+/**
+ * 購読者の表示名を更新する（cronから毎日キックされる）
+ */
+func refreshNames(w http.ResponseWriter, r *http.Request) {
+	c := appengine.NewContext(r)
+	refreshNamesWithContext(c, urlfetch.Client(c), w, r)
+}
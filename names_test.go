@@ -0,0 +1,126 @@
+package main
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/thingful/httpmock"
+	"google.golang.org/appengine"
+	"google.golang.org/appengine/aetest"
+	"google.golang.org/appengine/datastore"
+	"google.golang.org/appengine/urlfetch"
+)
+
+/**
+ * 表示名を定期的に更新する対象か
+ */
+func TestIsNameRefreshable(t *testing.T) {
+	type testParameter struct {
+		entity   subscriber
+		expected bool
+	}
+	testCases := []testParameter{{
+		entity:   subscriber{MID: "U00000000000000000000000000000000"},
+		expected: true,
+	}, {
+		entity:   subscriber{MID: "C00000000000000000000000000000000"},
+		expected: true,
+	}, {
+		entity:   subscriber{MID: "R00000000000000000000000000000000"}, // ルームは名前を取得できない
+		expected: false,
+	}, {
+		entity:   subscriber{MID: "C00000000000000000000000000000000", NameOverridden: true}, // `/set name`で設定済み
+		expected: false,
+	}, {
+		entity:   subscriber{MID: "C00000000000000000000000000000000", LeftAt: time.Now()}, // BOTが削除された
+		expected: false,
+	}}
+
+	for _, current := range testCases {
+		if actual := current.entity.isNameRefreshable(); actual != current.expected {
+			t.Errorf("Illegal return value. entity:%v, returnd:%v", current.entity, actual)
+		}
+	}
+}
+
+/**
+ * 購読者の表示名の更新
+ */
+func TestRefreshNames(t *testing.T) {
+	opt := aetest.Options{StronglyConsistentDatastore: true} //データストアに即反映
+	instance, err := aetest.NewInstance(&opt)
+	if err != nil {
+		t.Fatalf("Failed to create aetest instance: %v", err)
+	}
+	defer instance.Close()
+
+	req, err := instance.NewRequest("GET", "/cron/refreshnames", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Contextとhttp.Clientは、テストコード側でインスタンス化する（モックと共通のインスタンスを使う必要があるため）
+	ctx := appengine.NewContext(req)
+	client := urlfetch.Client(ctx)
+
+	// LINEへのGet Profile、Get Group Summaryリクエストをモックする
+	httpmock.ActivateNonDefault(client)
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterStubRequest(
+		httpmock.NewStubRequest(
+			"GET",
+			"https://api.line.me/v2/bot/profile/U00000000000000000000000000000001",
+			httpmock.NewStringResponder(200, `{"userId":"U00000000000000000000000000000001","displayName":"LINE jiro","pictureUrl":"","statusMessage":""}`),
+		),
+	)
+	httpmock.RegisterStubRequest(
+		httpmock.NewStubRequest(
+			"GET",
+			"https://api.line.me/v2/bot/group/C00000000000000000000000000000001/summary",
+			httpmock.NewStringResponder(200, `{"groupId":"C00000000000000000000000000000001","groupName":"新しいグループ名","pictureUrl":""}`),
+		),
+	)
+	httpmock.RegisterStubRequest(
+		httpmock.NewStubRequest(
+			"GET",
+			"https://api.line.me/v2/bot/group/C00000000000000000000000000000002/summary",
+			httpmock.NewStringResponder(404, `{"message":"Not found"}`),
+		),
+	)
+
+	// 更新される購読者、手動で設定した購読者、取得に失敗する購読者を用意しておく
+	entities := map[string]subscriber{
+		"U00000000000000000000000000000001": {DisplayName: "LINE taro"},
+		"C00000000000000000000000000000001": {DisplayName: "古いグループ名"},
+		"C00000000000000000000000000000002": {DisplayName: "取得できないグループ"},
+		"C00000000000000000000000000000003": {DisplayName: "手動で設定した名前", NameOverridden: true},
+	}
+	for mid, entity := range entities {
+		entity.MID = mid
+		if _, err = datastore.Put(ctx, datastore.NewKey(ctx, "Subscriber", mid, 0, nil), &entity); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// execute
+	res := httptest.NewRecorder()
+	refreshNamesWithContext(ctx, client, res, req) //モックと同じhttp.Clientインスタンスを渡す
+
+	// 取得できた名前だけが書き込まれていること
+	expected := map[string]string{
+		"U00000000000000000000000000000001": "LINE jiro",
+		"C00000000000000000000000000000001": "新しいグループ名",
+		"C00000000000000000000000000000002": "取得できないグループ",
+		"C00000000000000000000000000000003": "手動で設定した名前",
+	}
+	for mid, name := range expected {
+		var actual subscriber
+		if err = datastore.Get(ctx, datastore.NewKey(ctx, "Subscriber", mid, 0, nil), &actual); err != nil {
+			t.Fatal(err)
+		}
+		if actual.DisplayName != name {
+			t.Errorf("Unmatch entitiy's DisplayName. mid:%v DisplayName='%v'", mid, actual.DisplayName)
+		}
+	}
+}
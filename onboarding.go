@@ -0,0 +1,147 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"github.com/line/line-bot-sdk-go/linebot"
+
+	"google.golang.org/appengine"
+	"google.golang.org/appengine/log"
+	"google.golang.org/appengine/urlfetch"
+)
+
+// 初期設定の段階（購読者エンティティのOnboardingに保存する。空の場合は初期設定済み）
+const (
+	onboardingAwaitURL = "await_url" // 調整さんのURLを待っている（次のメッセージがURLであれば、`set chouseisan`なしで受け付ける）
+)
+
+/**
+ * 初期設定中に送られたメッセージが調整さんのURLだけであれば、調整さんハッシュを返す
+ *
+ * URLの形は`set chouseisan`コマンドと同じく許容する
+ */
+func isOnboardingURL(text string) (bool, string) {
+	fields := strings.Fields(text)
+	if len(fields) != 1 {
+		return false, ""
+	}
+	if hash := extractChouseisanHash(fields[0]); hash != "" {
+		return true, hash
+	}
+	return false, ""
+}
+
+/**
+ * 調整さんイベントの名前を返す。日程がなければ空文字
+ */
+func (m scheduleMap) eventTitle() string {
+	for _, s := range m {
+		return s.EventTitle
+	}
+	return ""
+}
+
+/**
+ * 友だち追加、招待時のメッセージ（BOTの説明と、調整さんのURLの入力のお願い）を組み立てて返す
+ *
+ * 最後のメッセージには、メニューのクイックリプライを付ける
+ */
+func constructWelcomeMessages(lang string, host string) []linebot.SendingMessage {
+	messages := newTextMessages(msg(lang, "onboarding.welcome", "https://"+host+"/"))
+	messages = append(messages, newTextMessages(msg(lang, "onboarding.ask_url"))...)
+	return withQuickReplies(messages, menuQuickReplyButtons(lang))
+}
+
+/**
+ * 調整さんイベントを設定したときの確認メッセージ（イベントの概要と、デフォルトのリマインドのタイミング）を組み立てて返す
+ */
+func (s *subscriber) constructOnboardingConfirmMessage(event string) string {
+	lang := s.lang()
+
+	timeZone := s.TimeZone
+	if timeZone == "" {
+		timeZone = defaultTimeZone
+	}
+
+	return msg(lang, "onboarding.confirm", event) + "\n\n" + msg(lang, "onboarding.timing", s.RemindBefore, s.RemindTime, timeZone)
+}
+
+/**
+ * 会話に貼られた調整さんのURLを、リマインドする調整さんイベントに設定するか確認するテキストを組み立てて返す
+ *
+ * 調整さんイベントが未設定か、別のイベントが設定されているかで文言を変える
+ */
+func constructPastedURLConfirmText(lang string, current string, hash string) string {
+	key := "onboarding.pasted_new"
+	if current != "" {
+		key = "onboarding.pasted_change"
+	}
+	return truncateText(msg(lang, key, "https://chouseisan.com/s?h="+hash), maxTemplateTextLength)
+}
+
+/**
+ * 会話に貼られた調整さんのURLを設定するか確認する、確認テンプレートのメッセージを返す
+ *
+ * 「設定する」を押すと`set chouseisan`コマンドを実行し、「キャンセル」を押すと何もしない
+ */
+func constructPastedURLConfirmMessage(lang string, current string, hash string) linebot.SendingMessage {
+	text := constructPastedURLConfirmText(lang, current, hash)
+	command := "set chouseisan https://chouseisan.com/s?h=" + hash
+	return linebot.NewTemplateMessage(
+		truncateText(text, maxAltTextLength),
+		linebot.NewConfirmTemplate(
+			text,
+			linebot.NewPostbackAction(msg(lang, "onboarding.pasted_set"), commandPostbackData(command), "", "/"+command),
+			linebot.NewPostbackAction(msg(lang, "onboarding.pasted_cancel"), cancelPostbackData(), "", ""),
+		),
+	)
+}
+
+/**
+ * スラッシュなしの、調整さんのURL（LINEが短縮したURLを含む）を含むメッセージを処理する
+ *
+ * 初期設定中に調整さんのURLだけが送られたら、そのまま受け付ける
+ * それ以外で、調整さんイベントが未設定か、別のイベントのURLが貼られたら、設定するか確認テンプレートで尋ねる（同じイベントのURLには反応しない）
+ *
+ * 引数にContextとhttp.Clientを取るインナーメソッド
+ */
+func onboardingWithContext(c context.Context, client *http.Client, w http.ResponseWriter, r *http.Request) {
+	mid := r.FormValue("mid")
+	token := r.FormValue("replyToken")
+	text := r.FormValue("text")
+	entity, err := readSubscriber(c, mid)
+	if err != nil || !entity.LeftAt.IsZero() {
+		return
+	}
+	if containsLineShortURL(text) {
+		text = expandLineShortURL(c, client, text)
+	}
+	if b, hash := isOnboardingURL(text); b && entity.Onboarding == onboardingAwaitURL {
+		setChouseisan(c, client, mid, token, hash)
+		return
+	}
+
+	hash := extractChouseisanHash(text)
+	if hash == "" || hash == entity.ChouseisanHash {
+		return
+	}
+	bot, err := createBotClient(c, client)
+	if err != nil {
+		return
+	}
+	message := constructPastedURLConfirmMessage(entity.lang(), entity.ChouseisanHash, hash)
+	if err := sendReply(bot, token, []linebot.SendingMessage{message}); err != nil {
+		log.Errorf(c, "Error occurred at reply confirm for pasted chouseisan URL. mid:%v err:%v", mid, err)
+	}
+}
+
+/**
+ * スラッシュなしのメッセージを処理する
+ */
+func onboarding(w http.ResponseWriter, r *http.Request) {
+	c := appengine.NewContext(r)
+	onboardingWithContext(c, urlfetch.Client(c), w, r)
+}
@@ -0,0 +1,317 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/line/line-bot-sdk-go/linebot"
+	"github.com/thingful/httpmock"
+	"google.golang.org/appengine"
+	"google.golang.org/appengine/aetest"
+	"google.golang.org/appengine/datastore"
+	"google.golang.org/appengine/urlfetch"
+)
+
+/**
+ * 初期設定中に送られた調整さんのURLの判定
+ */
+func TestIsOnboardingURL(t *testing.T) {
+	type testParameter struct {
+		text         string
+		expectedIs   bool
+		expectedHash string
+	}
+	testCases := []testParameter{{
+		text:         "https://chouseisan.com/s?h=3f7ffd73ba174332ae05bd363eba8e71",
+		expectedIs:   true,
+		expectedHash: "3f7ffd73ba174332ae05bd363eba8e71",
+	}, {
+		text:         " https://chouseisan.com/s?h=3f7ffd73ba174332ae05bd363eba8e71\n", // 前後にノイズがあってもtrue
+		expectedIs:   true,
+		expectedHash: "3f7ffd73ba174332ae05bd363eba8e71",
+	}, {
+		text:         "http://www.chouseisan.com/schedule/List?h=3f7ffd73ba174332ae05bd363eba8e71　", // URLの形と全角スペースは`set chouseisan`と同じく許容する
+		expectedIs:   true,
+		expectedHash: "3f7ffd73ba174332ae05bd363eba8e71",
+	}, {
+		text:         "調整さんはこちら https://chouseisan.com/s?h=3f7ffd73ba174332ae05bd363eba8e71", // URL以外の会話は受け付けない
+		expectedIs:   false,
+		expectedHash: "",
+	}}
+
+	for _, current := range testCases {
+		actualIs, actualHash := isOnboardingURL(current.text)
+		if actualIs != current.expectedIs {
+			t.Errorf("Illegal return value. text:%v, returnd:%v", current.text, actualIs)
+		}
+		if actualHash != current.expectedHash {
+			t.Errorf("Illegal return value. text:%v, returnd:%v", current.text, actualHash)
+		}
+	}
+}
+
+/**
+ * 友だち追加、招待時のメッセージ（最後のメッセージにメニューのクイックリプライを付ける）
+ */
+func TestConstructWelcomeMessages(t *testing.T) {
+	actual := constructWelcomeMessages(langJa, "example.appspot.com")
+	if len(actual) != 2 {
+		t.Fatalf("Unmatch messages count: %v", len(actual))
+	}
+	if text := actual[0].(*linebot.TextMessage).Text; !strings.Contains(text, "https://example.appspot.com/") {
+		t.Errorf("Usage URL not found: %v", text)
+	}
+	for i, expected := range []int{0, len(menuQuickReplyButtons(langJa))} {
+		body, err := json.Marshal(actual[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		var message struct {
+			QuickReply struct {
+				Items []json.RawMessage `json:"items"`
+			} `json:"quickReply"`
+		}
+		if err = json.Unmarshal(body, &message); err != nil {
+			t.Fatal(err)
+		}
+		if len(message.QuickReply.Items) != expected {
+			t.Errorf("Unmatch quick reply items count. index:%v, count:%v", i, len(message.QuickReply.Items))
+		}
+	}
+}
+
+/**
+ * 調整さんイベントを設定したときの確認メッセージ
+ */
+func TestConstructOnboardingConfirmMessage(t *testing.T) {
+	testdata := subscriber{
+		ChouseisanHash: "3f7ffd73ba174332ae05bd363eba8e71",
+		RemindBefore:   3,
+		RemindTime:     8,
+	}
+
+	expected := "調整さんイベント「忘年会」（候補日程2件、メンバー5名）をリマインドします\n\n" +
+		"開催3日前と当日の8:00（Asia/Tokyo）に、出欠状況をお知らせします。時刻を変えるときは、下のボタンから選んでください"
+	if actual := testdata.constructOnboardingConfirmMessage("「忘年会」（候補日程2件、メンバー5名）"); actual != expected {
+		t.Errorf("Unmatch message\nexpect:\n%v\nactual:\n%v", expected, actual)
+	}
+}
+
+/**
+ * 会話に貼られた調整さんのURLを設定するか確認するテキスト
+ */
+func TestConstructPastedURLConfirmText(t *testing.T) {
+	type testParameter struct {
+		current  string
+		expected string
+	}
+	testCases := []testParameter{{
+		current:  "", // 未設定
+		expected: "調整さんのURLが貼られました。リマインドする調整さんイベントに設定しますか？\nhttps://chouseisan.com/s?h=3f7ffd73ba174332ae05bd363eba8e71",
+	}, {
+		current:  "0123456789abcdef0123456789abcdef", // 別のイベントが設定済み
+		expected: "調整さんのURLが貼られました。リマインドする調整さんイベントを変更しますか？\nhttps://chouseisan.com/s?h=3f7ffd73ba174332ae05bd363eba8e71",
+	}}
+
+	for _, current := range testCases {
+		actual := constructPastedURLConfirmText(langJa, current.current, "3f7ffd73ba174332ae05bd363eba8e71")
+		if actual != current.expected {
+			t.Errorf("Unmatch message\nexpect:\n%v\nactual:\n%v", current.expected, actual)
+		}
+	}
+}
+
+/**
+ * 初期設定中に送られた調整さんのURLを、`set chouseisan`なしで受け付ける
+ */
+func TestOnboardingWithContext(t *testing.T) {
+	opt := aetest.Options{StronglyConsistentDatastore: true} //データストアに即反映
+	instance, err := aetest.NewInstance(&opt)
+	if err != nil {
+		t.Fatalf("Failed to create aetest instance: %v", err)
+	}
+	defer instance.Close()
+
+	hash := "3f7ffd73ba174332ae05bd363eba8e71"
+	mid := "C00000000000000000000000000000000"
+	form := url.Values{
+		"mid":        {mid},
+		"replyToken": {"00000000000000000000000000000000"},
+		"text":       {"https://chouseisan.com/s?h=" + hash},
+	}
+	req, err := instance.NewRequest("POST", "/task/onboarding", strings.NewReader(form.Encode()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded") //必須
+
+	// Contextとhttp.Clientは、テストコード側でインスタンス化する（モックと共通のインスタンスを使う必要があるため）
+	ctx := appengine.NewContext(req)
+	client := urlfetch.Client(ctx)
+
+	// 調整さんとLINEへのリクエストをモックする
+	httpmock.ActivateNonDefault(client)
+	defer httpmock.DeactivateAndReset()
+
+	actualSendMessages := []string{} //モックに送られたリプライメッセージを保持し、後で検証する
+	httpmock.RegisterStubRequest(
+		httpmock.NewStubRequest(
+			"GET",
+			"https://chouseisan.com/schedule/List/createCsv?h="+hash,
+			httpmock.NewStringResponder(200, readFile(t, "testdata/chouseisan/normally.csv")),
+		),
+	)
+	httpmock.RegisterStubRequest(
+		httpmock.NewStubRequest(
+			"POST",
+			"https://api.line.me/v2/bot/message/reply",
+			func(req *http.Request) (*http.Response, error) {
+				defer req.Body.Close()
+				if body, err := ioutil.ReadAll(req.Body); err == nil {
+					actualSendMessages = append(actualSendMessages, string(body))
+					return httpmock.NewStringResponse(200, "{}"), nil
+				}
+				return httpmock.NewStringResponse(500, "Unread post body"), nil
+			},
+		),
+	)
+
+	// 調整さんのURLを待っている購読者エンティティを用意しておく
+	entity := subscriber{
+		MID:          mid,
+		RemindBefore: 3,
+		RemindTime:   8,
+		TimeZone:     "Asia/Tokyo",
+		Onboarding:   onboardingAwaitURL,
+	}
+	key := datastore.NewKey(ctx, "Subscriber", mid, 0, nil)
+	if _, err = datastore.Put(ctx, key, &entity); err != nil {
+		t.Fatal(err)
+	}
+
+	// execute
+	res := httptest.NewRecorder()
+	onboardingWithContext(ctx, client, res, req) //モックと同じhttp.Clientインスタンスを渡す
+
+	// 調整さんハッシュが書き込まれ、初期設定が終わっていること
+	var actualEntity subscriber
+	if err = datastore.Get(ctx, key, &actualEntity); err != nil {
+		t.Fatal(err)
+	}
+	if actualEntity.ChouseisanHash != hash {
+		t.Errorf("Unmatch entitiy's chouseisan hash. hash='%v'", actualEntity.ChouseisanHash)
+	}
+	if actualEntity.Onboarding != "" {
+		t.Errorf("Onboarding was not completed. onboarding='%v'", actualEntity.Onboarding)
+	}
+
+	// csvから取り出したイベント名で確認のリプライを送ること
+	if len(actualSendMessages) != 1 || !strings.Contains(actualSendMessages[0], "「調整さんリマインダテストデータ」（候補日程6件、メンバー7名）") {
+		t.Errorf("Unmatch reply messages: %v", actualSendMessages)
+	}
+}
+
+/**
+ * 会話に貼られた調整さんのURLについて、設定するか確認テンプレートで尋ねる（同じイベントのURLには反応しない）
+ */
+func TestOnboardingWithContextPastedURL(t *testing.T) {
+	opt := aetest.Options{StronglyConsistentDatastore: true} //データストアに即反映
+	instance, err := aetest.NewInstance(&opt)
+	if err != nil {
+		t.Fatalf("Failed to create aetest instance: %v", err)
+	}
+	defer instance.Close()
+
+	hash := "3f7ffd73ba174332ae05bd363eba8e71"
+	mid := "C00000000000000000000000000000000"
+
+	type testParameter struct {
+		current       string // 設定済みの調整さんハッシュ
+		expectedReply bool   // 確認テンプレートをリプライするか
+	}
+	testCases := []testParameter{{
+		current:       "",
+		expectedReply: true,
+	}, {
+		current:       "0123456789abcdef0123456789abcdef",
+		expectedReply: true,
+	}, {
+		current:       hash,
+		expectedReply: false,
+	}}
+
+	for _, current := range testCases {
+		form := url.Values{
+			"mid":        {mid},
+			"replyToken": {"00000000000000000000000000000000"},
+			"text":       {"調整さんはこちら https://chouseisan.com/s?h=" + hash},
+		}
+		req, err := instance.NewRequest("POST", "/task/onboarding", strings.NewReader(form.Encode()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded") //必須
+
+		// Contextとhttp.Clientは、テストコード側でインスタンス化する（モックと共通のインスタンスを使う必要があるため）
+		ctx := appengine.NewContext(req)
+		client := urlfetch.Client(ctx)
+
+		// LINEへのリクエストをモックする
+		httpmock.ActivateNonDefault(client)
+		actualSendMessages := []string{} //モックに送られたリプライメッセージを保持し、後で検証する
+		httpmock.RegisterStubRequest(
+			httpmock.NewStubRequest(
+				"POST",
+				"https://api.line.me/v2/bot/message/reply",
+				func(req *http.Request) (*http.Response, error) {
+					defer req.Body.Close()
+					if body, err := ioutil.ReadAll(req.Body); err == nil {
+						actualSendMessages = append(actualSendMessages, string(body))
+						return httpmock.NewStringResponse(200, "{}"), nil
+					}
+					return httpmock.NewStringResponse(500, "Unread post body"), nil
+				},
+			),
+		)
+
+		entity := subscriber{
+			MID:            mid,
+			ChouseisanHash: current.current,
+			RemindBefore:   3,
+			RemindTime:     8,
+		}
+		key := datastore.NewKey(ctx, "Subscriber", mid, 0, nil)
+		if _, err = datastore.Put(ctx, key, &entity); err != nil {
+			t.Fatal(err)
+		}
+
+		// execute
+		res := httptest.NewRecorder()
+		onboardingWithContext(ctx, client, res, req) //モックと同じhttp.Clientインスタンスを渡す
+		httpmock.DeactivateAndReset()
+
+		// 確認テンプレートには、`set chouseisan`を実行するpostbackが含まれること
+		if current.expectedReply {
+			if len(actualSendMessages) != 1 || !strings.Contains(actualSendMessages[0], `"type":"confirm"`) ||
+				!strings.Contains(actualSendMessages[0], "/set chouseisan https://chouseisan.com/s?h="+hash) {
+				t.Errorf("Unmatch reply messages. current:%v messages:%v", current.current, actualSendMessages)
+			}
+		} else if len(actualSendMessages) != 0 {
+			t.Errorf("Unexpected reply messages. current:%v messages:%v", current.current, actualSendMessages)
+		}
+
+		// 確認するだけで、調整さんハッシュは書き換えないこと
+		var actualEntity subscriber
+		if err = datastore.Get(ctx, key, &actualEntity); err != nil {
+			t.Fatal(err)
+		}
+		if actualEntity.ChouseisanHash != current.current {
+			t.Errorf("Unmatch entitiy's chouseisan hash. hash='%v'", actualEntity.ChouseisanHash)
+		}
+	}
+}
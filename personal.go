@@ -0,0 +1,72 @@
+package main
+
+import (
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/line/line-bot-sdk-go/linebot"
+
+	"google.golang.org/appengine/log"
+)
+
+/**
+ * メンバーの出欠が未入力もしくは△の、当日以降の日程を開催日順に返す
+ */
+func (m scheduleMap) pendingAnswers(name string, today time.Time) []snapshotAnswer {
+	from := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, today.Location())
+
+	result := []snapshotAnswer{}
+	for _, v := range m.snapshotAnswers() {
+		if v.Name != name || (v.Answer != "" && v.Answer != "△") {
+			continue
+		}
+		if m[v.Date].Date.Before(from) {
+			continue
+		}
+		result = append(result, v)
+	}
+	return result
+}
+
+/**
+ * 個別通知のメッセージを組み立てて返す（言語はグループの設定に従う）
+ */
+func constructPersonalMessage(lang string, groupName string, deadline time.Time, name string, pending []snapshotAnswer, hash string) string {
+	message := msg(lang, "personal.deadline", groupName, int(deadline.Month()), deadline.Day()) +
+		"\n\n" + msg(lang, "personal.pending", name)
+	for _, v := range pending {
+		message += "\n" + v.DateString + " " + answerLabel(lang, v.Answer)
+	}
+	return message + "\n\n" + msg(lang, "deadline.enter", "https://chouseisan.com/s?h="+hash)
+}
+
+/**
+ * 回答期限のN日前であれば、個別通知を受け取るメンバーに、未入力/△の日程を1:1トークで通知する
+ */
+func remindPersonal(c context.Context, bot *linebot.Client, current *subscriber, m scheduleMap, today time.Time) {
+	if !current.isDeadlineRemindDay(today) {
+		return
+	}
+
+	links, err := queryMemberLinks(c, current.MID)
+	if err != nil {
+		return
+	}
+	for _, v := range links {
+		if !v.Personal {
+			continue
+		}
+		pending := m.pendingAnswers(v.Name, today.In(current.location()))
+		if len(pending) == 0 {
+			continue
+		}
+
+		log.Infof(c, "Remind personal! subscriber:%v name:%v pending:%v", current.DisplayName, v.Name, len(pending))
+		message := constructPersonalMessage(current.lang(), current.DisplayName, current.Deadline.In(current.location()), v.Name, pending, current.ChouseisanHash)
+		if err := sendPush(bot, v.UID, newTextMessages(message)); err != nil {
+			// BOTと友だちになっていないユーザには送信できない
+			log.Warningf(c, "Error occurred at remind personal. subscriber:%v, name:%v, err: %v", current.DisplayName, v.Name, err)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+/**
+ * メンバーの出欠が未入力/△の日程の抽出と、個別通知のメッセージの組み立て
+ */
+func TestConstructPersonalMessage(t *testing.T) {
+	tz, _ := time.LoadLocation("Asia/Tokyo")
+	m := scheduleMap{}
+	for _, s := range []schedule{
+		{Date: time.Date(2016, time.December, 17, 0, 0, 0, 0, tz), DateString: "12/17(土)", Names: []string{"電一", "電次郎"}, Answers: map[string]string{"電一": "", "電次郎": ""}},
+		{Date: time.Date(2016, time.December, 24, 0, 0, 0, 0, tz), DateString: "12/24(土)", Names: []string{"電一", "電次郎"}, Answers: map[string]string{"電一": "○", "電次郎": "△"}},
+		{Date: time.Date(2016, time.December, 25, 0, 0, 0, 0, tz), DateString: "12/25(日)", Names: []string{"電一", "電次郎"}, Answers: map[string]string{"電一": "○", "電次郎": "×"}},
+		{Date: time.Date(2016, time.December, 31, 0, 0, 0, 0, tz), DateString: "12/31(土)", Names: []string{"電一", "電次郎"}, Answers: map[string]string{"電一": "○", "電次郎": ""}},
+	} {
+		m[s.Date.String()] = s
+	}
+
+	// 過去の日程、○/×の日程は対象外
+	pending := m.pendingAnswers("電次郎", time.Date(2016, time.December, 19, 8, 0, 0, 0, tz))
+	if len(pending) != 2 {
+		t.Fatalf("Unmatch pending answers: %v", pending)
+	}
+
+	expected := "「テストグループ」の調整さんイベントの回答期限は12/20です\n\n" +
+		"電次郎さんの出欠が未入力/△の日程:\n12/24(土) △\n12/31(土) 未入力\n\n" +
+		"出欠の入力は「調整さん」へ\nhttps://chouseisan.com/s?h=3f7ffd73ba174332ae05bd363eba8e71"
+	actual := constructPersonalMessage(langJa, "テストグループ", time.Date(2016, time.December, 20, 0, 0, 0, 0, tz), "電次郎", pending, "3f7ffd73ba174332ae05bd363eba8e71")
+	if actual != expected {
+		t.Errorf("Unmatch personal message\nexpect:\n%v\nactual:\n%v", expected, actual)
+	}
+
+	// 英語のグループ
+	expected = "The answer deadline of the chouseisan event of \"テストグループ\" is 12/20\n\n" +
+		"Dates 電次郎 has not answered or answered △:\n12/24(土) △\n12/31(土) no answer\n\n" +
+		"Enter your attendance on chouseisan\nhttps://chouseisan.com/s?h=3f7ffd73ba174332ae05bd363eba8e71"
+	actual = constructPersonalMessage(langEn, "テストグループ", time.Date(2016, time.December, 20, 0, 0, 0, 0, tz), "電次郎", pending, "3f7ffd73ba174332ae05bd363eba8e71")
+	if actual != expected {
+		t.Errorf("Unmatch personal message\nexpect:\n%v\nactual:\n%v", expected, actual)
+	}
+}
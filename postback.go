@@ -0,0 +1,105 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/net/context"
+
+	"google.golang.org/appengine"
+	"google.golang.org/appengine/log"
+	"google.golang.org/appengine/urlfetch"
+)
+
+// postbackの操作
+const (
+	postbackActionCommand = "command" // コマンドの実行（textにスラッシュコマンドと同じ文字列を指定）
+	postbackActionCancel  = "cancel"  // 何もしない（確認テンプレートの「キャンセル」ボタン）
+)
+
+// ボタンやメニューから送られるpostbackのデータ（url.Valuesの形式でエンコードする）
+type postbackData struct {
+	Action string // 操作
+	Text   string // コマンド（先頭の"/"は不要）
+}
+
+/**
+ * postbackのデータをエンコードして返す（空の項目は含めない）
+ */
+func (p postbackData) encode() string {
+	values := url.Values{"action": {p.Action}}
+	if len(p.Text) > 0 {
+		values.Set("text", p.Text)
+	}
+	return values.Encode()
+}
+
+/**
+ * postbackのデータを解析して返す。操作ごとに必要な項目が揃っていなければエラー
+ */
+func parsePostbackData(data string) (postbackData, error) {
+	values, err := url.ParseQuery(data)
+	if err != nil {
+		return postbackData{}, err
+	}
+	p := postbackData{
+		Action: values.Get("action"),
+		Text:   values.Get("text"),
+	}
+
+	switch p.Action {
+	case postbackActionCommand:
+		if len(p.Text) == 0 {
+			return p, errors.New("text is required")
+		}
+	case postbackActionCancel:
+	default:
+		return p, errors.New("unknown action: " + p.Action)
+	}
+	return p, nil
+}
+
+/**
+ * コマンドを実行するpostbackデータを組み立てて返す
+ */
+func commandPostbackData(text string) string {
+	return postbackData{Action: postbackActionCommand, Text: text}.encode()
+}
+
+/**
+ * 何もしないpostbackデータを組み立てて返す
+ */
+func cancelPostbackData() string {
+	return postbackData{Action: postbackActionCancel}.encode()
+}
+
+/**
+ * postbackを解析して、操作ごとの処理を実行する
+ *
+ * 引数にContextとhttp.Clientを取るインナーメソッド
+ */
+func postbackWithContext(c context.Context, client *http.Client, w http.ResponseWriter, r *http.Request) {
+	p, err := parsePostbackData(r.FormValue("data"))
+	if err != nil {
+		log.Warningf(c, "Invalid postback data. data:%v err:%v", r.FormValue("data"), err)
+		return
+	}
+
+	switch p.Action {
+	case postbackActionCommand:
+		// スラッシュコマンドと同じ処理を実行する
+		r.Form.Set("text", p.Text)
+		commandAnalyzeWithContext(c, client, w, r)
+	case postbackActionCancel:
+		log.Infof(c, "Postback was canceled. mid:%v", r.FormValue("mid"))
+	}
+}
+
+/**
+ * postbackを解析して、操作ごとの処理を実行する（Task Queueからキックされる）
+ */
+func postback(w http.ResponseWriter, r *http.Request) {
+	c := appengine.NewContext(r)
+	postbackWithContext(c, urlfetch.Client(c), w, r)
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/thingful/httpmock"
+	"google.golang.org/appengine"
+	"google.golang.org/appengine/aetest"
+	"google.golang.org/appengine/datastore"
+	"google.golang.org/appengine/urlfetch"
+)
+
+/**
+ * postbackデータの組み立てと解析
+ */
+func TestParsePostbackData(t *testing.T) {
+	type testParameter struct {
+		data     string
+		expected postbackData
+		isError  bool
+	}
+	testCases := []testParameter{{
+		data:     commandPostbackData("set notify changes on"),
+		expected: postbackData{Action: postbackActionCommand, Text: "set notify changes on"},
+	}, {
+		data:     cancelPostbackData(),
+		expected: postbackData{Action: postbackActionCancel},
+	}, {
+		data:    "action=answer&hash=3f7ffd73ba174332ae05bd363eba8e71&date=2016-12-24&answer=○", // 出欠の登録（対象外）
+		isError: true,
+	}, {
+		data:    "action=command", // コマンドの指定なし
+		isError: true,
+	}, {
+		data:    "action=unknown",
+		isError: true,
+	}}
+
+	for _, current := range testCases {
+		actual, err := parsePostbackData(current.data)
+		if (err != nil) != current.isError {
+			t.Errorf("Illegal return value. data:%v, err:%v", current.data, err)
+		}
+		if !current.isError && actual != current.expected {
+			t.Errorf("Illegal return value. data:%v, returnd:%+v", current.data, actual)
+		}
+	}
+}
+
+/**
+ * postbackからのコマンド実行のテスト（スラッシュコマンドと同じ処理が実行されること）
+ */
+func TestPostbackCommand(t *testing.T) {
+	opt := aetest.Options{StronglyConsistentDatastore: true} //データストアに即反映
+	instance, err := aetest.NewInstance(&opt)
+	if err != nil {
+		t.Fatalf("Failed to create aetest instance: %v", err)
+	}
+	defer instance.Close()
+
+	mid := "C00000000000000000000000000000000"
+	form := url.Values{
+		"mid":        {mid},
+		"uid":        {"U00000000000000000000000000000001"},
+		"replyToken": {"00000000000000000000000000000000"},
+		"data":       {commandPostbackData("set notify changes on")},
+	}
+	req, err := instance.NewRequest("POST", "/task/postback", strings.NewReader(form.Encode()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded") //必須
+
+	// Contextとhttp.Clientは、テストコード側でインスタンス化する（モックと共通のインスタンスを使う必要があるため）
+	c := appengine.NewContext(req)
+	client := urlfetch.Client(c)
+
+	// LINEへのReply Messageリクエストをモックする
+	httpmock.ActivateNonDefault(client)
+	defer httpmock.DeactivateAndReset()
+	actualSendMessages := []string{} //モックに送られたリプライメッセージを保持し、後で検証する
+	httpmock.RegisterStubRequest(
+		httpmock.NewStubRequest(
+			"POST",
+			"https://api.line.me/v2/bot/message/reply",
+			func(req *http.Request) (*http.Response, error) {
+				defer req.Body.Close()
+				if body, err := ioutil.ReadAll(req.Body); err == nil {
+					actualSendMessages = append(actualSendMessages, string(body))
+					return httpmock.NewStringResponse(200, "{}"), nil
+				}
+				return httpmock.NewStringResponse(500, "Unread post body"), nil
+			},
+		),
+	)
+
+	// 更新される購読者エンティティを用意しておく
+	entity := subscriber{
+		MID: mid,
+	}
+	key := datastore.NewKey(c, "Subscriber", mid, 0, nil)
+	if _, err = datastore.Put(c, key, &entity); err != nil {
+		t.Fatal(err)
+	}
+
+	// execute
+	res := httptest.NewRecorder()
+	postbackWithContext(c, client, res, req) //モックと同じhttp.Clientインスタンスを渡す
+
+	// スタブがすべて呼ばれたことを検証
+	if err = httpmock.AllStubsCalled(); err != nil {
+		t.Errorf("Not all stubs were called: %s", err)
+	}
+
+	// スラッシュコマンドと同じ結果をリプライしていること
+	if len(actualSendMessages) != 1 || !strings.Contains(actualSendMessages[0], "リマインドした日程の出欠が変更されたら、お知らせするように設定しました") {
+		t.Errorf("Unmatch reply messages: %v", actualSendMessages)
+	}
+
+	// データストアに設定が書き込まれていること
+	var actualEntity subscriber
+	if err = datastore.Get(c, key, &actualEntity); err != nil {
+		t.Fatal(err)
+	}
+	if !actualEntity.NotifyChanges {
+		t.Errorf("Unmatch entitiy's notify changes. notify='%v'", actualEntity.NotifyChanges)
+	}
+}
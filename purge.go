@@ -0,0 +1,75 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"google.golang.org/appengine"
+	"google.golang.org/appengine/datastore"
+	"google.golang.org/appengine/log"
+	"google.golang.org/appengine/urlfetch"
+)
+
+// BOTが削除された購読者の設定を保持する期間（この期間内に再招待されれば、設定を引き継ぐ）
+const subscriberRetention = 30 * 24 * time.Hour
+
+/**
+ * BOTが削除されてから保持期間内であればtrueを返す
+ */
+func (s *subscriber) isRestorable(now time.Time) bool {
+	return !s.LeftAt.IsZero() && now.Sub(s.LeftAt) <= subscriberRetention
+}
+
+/**
+ * 購読者と、購読者に紐付くエンティティ（メンバーの紐付け、出欠スナップショット）を削除する
+ */
+func purgeSubscriber(c context.Context, mid string) error {
+	keys, err := datastore.NewQuery("MemberLink").Filter("MID =", mid).KeysOnly().GetAll(c, nil)
+	if err != nil {
+		log.Errorf(c, "Error occurred at query MemberLink entity. mid:%v err:%v", mid, err)
+		return err
+	}
+	keys = append(keys,
+		datastore.NewKey(c, "AnswerSnapshot", mid, 0, nil),
+		datastore.NewKey(c, "Subscriber", mid, 0, nil),
+	)
+	// 存在しないキー（出欠スナップショットがないなど）の削除はエラーにならない
+	if err := datastore.DeleteMulti(c, keys); err != nil {
+		log.Errorf(c, "Error occurred at delete entities of subscriber. mid:%v err:%v", mid, err)
+		return err
+	}
+	return nil
+}
+
+/**
+ * 保持期間が過ぎた、BOTが削除された購読者を削除する
+ *
+ * 引数にContextとhttp.Clientを取るインナーメソッド
+ */
+func purgeSubscribersWithContext(c context.Context, client *http.Client, w http.ResponseWriter, r *http.Request) {
+	expired := time.Now().Add(-subscriberRetention)
+
+	// LeftAtがゼロ値（参加中）の購読者は除く
+	q := datastore.NewQuery("Subscriber").Filter("LeftAt >", time.Time{}).Filter("LeftAt <", expired)
+	var entities []subscriber
+	if _, err := q.GetAll(c, &entities); err != nil {
+		log.Errorf(c, "Error occurred at query Subscriber entity. err:%v", err)
+		return
+	}
+	for _, v := range entities {
+		if err := purgeSubscriber(c, v.MID); err != nil {
+			continue
+		}
+		log.Infof(c, "Purge subscriber left at %v. subscriber:%v mid:%v", v.LeftAt, v.DisplayName, v.MID)
+	}
+}
+
+/**
+ * 保持期間が過ぎた、BOTが削除された購読者を削除する（cronから毎日キックされる）
+ */
+func purgeSubscribers(w http.ResponseWriter, r *http.Request) {
+	c := appengine.NewContext(r)
+	purgeSubscribersWithContext(c, urlfetch.Client(c), w, r)
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"google.golang.org/appengine"
+	"google.golang.org/appengine/aetest"
+	"google.golang.org/appengine/datastore"
+	"google.golang.org/appengine/urlfetch"
+)
+
+/**
+ * 再招待されたときに、設定を引き継げるかの判定
+ */
+func TestIsRestorable(t *testing.T) {
+	now := time.Date(2016, time.December, 20, 8, 0, 0, 0, time.UTC)
+
+	type testParameter struct {
+		leftAt   time.Time
+		expected bool
+	}
+	testCases := []testParameter{{
+		leftAt:   time.Time{}, // 参加中
+		expected: false,
+	}, {
+		leftAt:   now.Add(-24 * time.Hour),
+		expected: true,
+	}, {
+		leftAt:   now.Add(-subscriberRetention), // 保持期間ちょうど
+		expected: true,
+	}, {
+		leftAt:   now.Add(-subscriberRetention - time.Hour), // 保持期間切れ
+		expected: false,
+	}}
+
+	for _, current := range testCases {
+		s := subscriber{LeftAt: current.leftAt}
+		if actual := s.isRestorable(now); actual != current.expected {
+			t.Errorf("Illegal return value. leftAt:%v, returnd:%v", current.leftAt, actual)
+		}
+	}
+}
+
+/**
+ * 保持期間が過ぎた購読者と、紐付くエンティティの削除
+ */
+func TestPurgeSubscribers(t *testing.T) {
+	opt := aetest.Options{StronglyConsistentDatastore: true} //データストアに即反映
+	instance, err := aetest.NewInstance(&opt)
+	if err != nil {
+		t.Fatalf("Failed to create aetest instance: %v", err)
+	}
+	defer instance.Close()
+
+	req, err := instance.NewRequest("GET", "/cron/purgesubscribers", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := appengine.NewContext(req)
+
+	// 参加中、保持期間内、保持期間切れの購読者を用意しておく
+	entities := map[string]subscriber{
+		"C00000000000000000000000000000001": {LeftAt: time.Time{}},
+		"C00000000000000000000000000000002": {LeftAt: time.Now().Add(-24 * time.Hour)},
+		"C00000000000000000000000000000003": {LeftAt: time.Now().Add(-subscriberRetention - 24*time.Hour)},
+	}
+	for mid, entity := range entities {
+		entity.MID = mid
+		if _, err = datastore.Put(ctx, datastore.NewKey(ctx, "Subscriber", mid, 0, nil), &entity); err != nil {
+			t.Fatal(err)
+		}
+		if err := writeMemberLink(ctx, mid, "U00000000000000000000000000000001", "電一", "でんいち"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// execute
+	res := httptest.NewRecorder()
+	purgeSubscribersWithContext(ctx, urlfetch.Client(ctx), res, req)
+
+	// 保持期間切れの購読者と、メンバーの紐付けだけが削除されていること
+	subscribers := []subscriber{}
+	if _, err = datastore.NewQuery("Subscriber").GetAll(ctx, &subscribers); err != nil {
+		t.Fatal(err)
+	}
+	if len(subscribers) != 2 {
+		t.Errorf("Unmatch number of subscribers: %v", len(subscribers))
+	}
+	for _, v := range subscribers {
+		if v.MID == "C00000000000000000000000000000003" {
+			t.Errorf("Expired subscriber was not purged")
+		}
+	}
+	links := []memberLink{}
+	if _, err = datastore.NewQuery("MemberLink").GetAll(ctx, &links); err != nil {
+		t.Fatal(err)
+	}
+	if len(links) != 2 {
+		t.Errorf("Unmatch number of member links: %v", len(links))
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/line/line-bot-sdk-go/linebot"
+)
+
+/**
+ * 押すとコマンドを実行する（postbackを送る）クイックリプライのボタンを返す
+ *
+ * トークには、スラッシュコマンドと同じ文字列が表示される
+ */
+func newCommandQuickReplyButton(label string, text string) *linebot.QuickReplyButton {
+	return linebot.NewQuickReplyButton("", linebot.NewPostbackAction(truncateText(label, maxQuickReplyLabel), commandPostbackData(text), "", "/"+text))
+}
+
+/**
+ * 友だち追加、招待時および`help`コマンドで表示するメニューのボタンを返す
+ */
+func menuQuickReplyButtons(lang string) []*linebot.QuickReplyButton {
+	return []*linebot.QuickReplyButton{
+		newCommandQuickReplyButton(msg(lang, "menu.remind_now"), "remind now"),
+		newCommandQuickReplyButton(msg(lang, "menu.show_settings"), "show settings"),
+		newCommandQuickReplyButton(msg(lang, "menu.set_remindtime"), "set remindtime"),
+	}
+}
+
+/**
+ * リマインド時刻の選択肢のボタンを返す
+ */
+func remindTimeQuickReplyButtons() []*linebot.QuickReplyButton {
+	buttons := []*linebot.QuickReplyButton{}
+	for _, hour := range remindTimeChoices {
+		buttons = append(buttons, newCommandQuickReplyButton(strconv.Itoa(hour)+":00", "set remindtime "+strconv.Itoa(hour)))
+	}
+	return buttons
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"testing"
+	"unicode/utf8"
+
+	"github.com/line/line-bot-sdk-go/linebot"
+)
+
+/**
+ * クイックリプライのボタンがLINEの制限内に収まり、実行するコマンドが有効であること
+ */
+func TestQuickReplyButtons(t *testing.T) {
+	for _, buttons := range [][]*linebot.QuickReplyButton{menuQuickReplyButtons(langJa), menuQuickReplyButtons(langEn), remindTimeQuickReplyButtons()} {
+		if len(buttons) > maxQuickReplyItems {
+			t.Errorf("Too many quick reply items: %v", len(buttons))
+		}
+		for _, button := range buttons {
+			action := button.Action.(*linebot.PostbackAction)
+			if utf8.RuneCountInString(action.Label) > maxQuickReplyLabel {
+				t.Errorf("Too long label: %v", action.Label)
+			}
+			if _, err := parsePostbackData(action.Data); err != nil {
+				t.Errorf("Invalid postback data. data:%v err:%v", action.Data, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/line/line-bot-sdk-go/linebot"
+
+	"google.golang.org/appengine/log"
+)
+
+// テンプレートの最大文字数（データストアのnoindexの文字列に収まる長さ）
+const maxReminderTemplateLength = 1000
+
+// テンプレートのrange、withの入れ子の最大の深さ（rangeを重ねると、出力がなくても処理が指数的に増えるため）
+const maxReminderTemplateDepth = 2
+
+// テンプレートを検証、適用できないときのエラー（返信では言語に合わせた文言にする）
+var (
+	errTemplateTooLong       = errors.New("reminder template is too long")
+	errTemplateCall          = errors.New("{{template}} is not allowed")
+	errTemplateDefine        = errors.New("{{define}} and {{block}} are not allowed")
+	errTemplateTooDeep       = errors.New("range and with are nested too deeply")
+	errTemplateOutputTooLong = errors.New("rendered reminder is too long")
+	errTemplateOutputEmpty   = errors.New("rendered reminder is empty")
+)
+
+// リマインドのテンプレートで使えるフィールド（scheduleから必要なもののみ渡す）
+type reminderTemplateData struct {
+	EventTitle   string   // 調整さんのイベント名
+	Date         string   // 日程欄（例: "12/24(土) 19:00〜"）
+	Present      int      // ○の人数
+	Absent       int      // ×の人数
+	Maybe        int      // △の人数
+	Unanswered   int      // 未入力の人数
+	Participants string   // ○のメンバーの名前（","区切り、長い場合は「他N名」と省略）
+	Absentees    string   // ×のメンバーの名前
+	Undecided    string   // △および未入力のメンバーの名前
+	URL          string   // 調整さんのURL
+	Names        []string // メンバーの名前（rangeで使う）
+}
+
+/**
+ * テンプレートに渡すデータを組み立てて返す
+ */
+func (s *schedule) reminderTemplateData(hash string) reminderTemplateData {
+	undecided := []string{}
+	for _, name := range s.Names {
+		if answer := s.Answers[name]; answer == "" || answer == "△" {
+			undecided = append(undecided, name)
+		}
+	}
+	return reminderTemplateData{
+		EventTitle:   s.EventTitle,
+		Date:         s.DateString,
+		Present:      len(s.namesByAnswer("○")),
+		Absent:       len(s.namesByAnswer("×")),
+		Maybe:        len(s.namesByAnswer("△")),
+		Unanswered:   len(s.namesByAnswer("")),
+		Participants: truncateNames(s.namesByAnswer("○"), ",", maxNamesLength),
+		Absentees:    truncateNames(s.namesByAnswer("×"), ",", maxNamesLength),
+		Undecided:    truncateNames(undecided, ",", maxNamesLength),
+		URL:          "https://chouseisan.com/s?h=" + hash,
+		Names:        s.Names,
+	}
+}
+
+/**
+ * テンプレートの構文木に、使えないアクション（別テンプレートの呼び出し）や、深すぎるrange、withの入れ子が含まれていればエラーを返す
+ *
+ * depthは、nodeを囲んでいるrange、withの数
+ */
+func checkTemplateNode(node parse.Node, depth int) error {
+	switch n := node.(type) {
+	case *parse.TemplateNode:
+		return errTemplateCall
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, v := range n.Nodes {
+			if err := checkTemplateNode(v, depth); err != nil {
+				return err
+			}
+		}
+	case *parse.IfNode:
+		return checkBranchNode(&n.BranchNode, depth)
+	case *parse.RangeNode:
+		return checkBranchNode(&n.BranchNode, depth+1)
+	case *parse.WithNode:
+		return checkBranchNode(&n.BranchNode, depth+1)
+	}
+	return nil
+}
+
+func checkBranchNode(n *parse.BranchNode, depth int) error {
+	if depth > maxReminderTemplateDepth {
+		return errTemplateTooDeep
+	}
+	if err := checkTemplateNode(n.List, depth); err != nil {
+		return err
+	}
+	return checkTemplateNode(n.ElseList, depth)
+}
+
+/**
+ * リマインドのテンプレートをパースして返す
+ *
+ * 使えるのはreminderTemplateDataのフィールドと標準の関数のみ。{{define}}、{{template}}、{{block}}は使えず、range、withの入れ子は2段まで
+ */
+func parseReminderTemplate(text string) (*template.Template, error) {
+	if len([]rune(text)) > maxReminderTemplateLength {
+		return nil, errTemplateTooLong
+	}
+	t, err := template.New("reminder").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	if len(t.Templates()) > 1 {
+		return nil, errTemplateDefine
+	}
+	if err = checkTemplateNode(t.Tree.Root, 0); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// 出力が上限を超えたら、それ以上書き込まずにエラーを返すWriter
+type limitedBuffer struct {
+	bytes.Buffer
+	limit    int
+	exceeded bool // 上限を超えたか（text/templateが書き込みのエラーを包んで返す場合があるため）
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.Len()+len(p) > b.limit {
+		b.exceeded = true
+		return 0, errTemplateOutputTooLong
+	}
+	return b.Buffer.Write(p)
+}
+
+/**
+ * リマインドのテンプレートを適用した文字列を返す
+ */
+func renderReminderTemplate(text string, data reminderTemplateData) (string, error) {
+	t, err := parseReminderTemplate(text)
+	if err != nil {
+		return "", err
+	}
+	buf := &limitedBuffer{limit: maxTextLength * 4} //UTF-8で最大4バイト
+	if err = t.Execute(buf, data); buf.exceeded {
+		return "", errTemplateOutputTooLong
+	} else if err != nil {
+		return "", err
+	}
+	rendered := strings.TrimSpace(buf.String())
+	if rendered == "" {
+		return "", errTemplateOutputEmpty
+	}
+	return truncateText(rendered, maxTextLength), nil
+}
+
+/**
+ * プレビュー、テンプレートの検証に使うサンプルの日程
+ */
+func sampleSchedule() schedule {
+	return schedule{
+		EventTitle: "調整さんリマインダ",
+		Date:       time.Date(2016, time.December, 24, 0, 0, 0, 0, time.UTC),
+		DateString: "12/24(土) 19:00〜",
+		Names:      []string{"電一", "電次郎", "電三太郎", "電四郎", "電五郎"},
+		Answers:    map[string]string{"電一": "△", "電次郎": "×", "電三太郎": "○", "電四郎": "○", "電五郎": ""},
+	}
+}
+
+/**
+ * テンプレートを適用したテキストのリマインド用のFlex Messageバブルを組み立てて返す
+ *
+ * ボディにテンプレートを適用したテキスト、フッタにカードと同じ調整さんへのボタンを配置する（テンプレートでもボタンから入力できるように）
+ */
+func (s *schedule) constructTemplateBubble(lang string, hash string, text string) flexBubble {
+	body := flexComponent{Type: "box", Layout: "vertical", Contents: []flexComponent{{Type: "text", Text: text, Wrap: true}}}
+	footer := s.constructAnswerFooter(lang, hash)
+	return flexBubble{Type: "bubble", Body: &body, Footer: &footer}
+}
+
+/**
+ * 購読者の設定に従って、リマインドのメッセージを組み立てて返す
+ *
+ * テンプレートが設定されていればテンプレートを適用したテキストのバブル（代替テキストも同じテキスト）、設定されていないか適用に失敗した場合はFlex Messageのカード
+ */
+func (s *schedule) constructReminderMessageFor(c context.Context, current *subscriber) (linebot.SendingMessage, error) {
+	if len(current.ReminderTemplate) > 0 {
+		text, err := renderReminderTemplate(current.ReminderTemplate, s.reminderTemplateData(current.ChouseisanHash))
+		if err == nil {
+			return newFlexMessage(text, s.constructTemplateBubble(current.lang(), current.ChouseisanHash, text))
+		}
+		log.Warningf(c, "Error occurred at render reminder template, fallback to default. subscriber:%v, err: %v", current.DisplayName, err)
+	}
+	return s.constructReminderMessage(current.lang(), current.ChouseisanHash)
+}
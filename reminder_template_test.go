@@ -0,0 +1,132 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+/**
+ * リマインドのテンプレートの適用
+ */
+func TestRenderReminderTemplate(t *testing.T) {
+	sample := sampleSchedule()
+	data := sample.reminderTemplateData("3f7ffd73ba174332ae05bd363eba8e71")
+
+	type testParameter struct {
+		template string
+		expected string
+		isError  bool
+	}
+	testCases := []testParameter{{
+		template: "【{{.EventTitle}}】{{.Date}}\n○{{.Present}} △{{.Maybe}} ×{{.Absent}} 未{{.Unanswered}}\n未定: {{.Undecided}}\n{{.URL}}",
+		expected: "【調整さんリマインダ】12/24(土) 19:00〜\n○2 △1 ×1 未1\n未定: 電一,電五郎\nhttps://chouseisan.com/s?h=3f7ffd73ba174332ae05bd363eba8e71",
+	}, {
+		template: "{{range .Names}}{{.}} {{end}}", // rangeは使える
+		expected: "電一 電次郎 電三太郎 電四郎 電五郎",
+	}, {
+		template: "{{with .EventTitle}}{{range $.Names}}{{.}} {{end}}{{end}}", // range、withの入れ子は2段まで
+		expected: "電一 電次郎 電三太郎 電四郎 電五郎",
+	}, {
+		template: "{{.Unknown}}", // 存在しないフィールド
+		isError:  true,
+	}, {
+		template: "{{.Date", // 構文エラー
+		isError:  true,
+	}, {
+		template: `{{define "a"}}{{template "a"}}{{end}}{{template "a"}}`, // 別テンプレートの定義、呼び出し
+		isError:  true,
+	}, {
+		template: `{{if .Present}}{{template "reminder"}}{{end}}`, // 自身の呼び出し
+		isError:  true,
+	}, {
+		template: "{{range .Names}}{{printf \"%9999s\" .}}{{end}}", // 出力が長すぎる
+		isError:  true,
+	}, {
+		template: "{{range .Names}}{{range $.Names}}{{range $.Names}}{{end}}{{end}}{{end}}", // 出力がなくても処理が増える深い入れ子
+		isError:  true,
+	}, {
+		template: "{{range .Names}}{{else}}{{with .}}{{range .}}{{end}}{{end}}{{end}}", // elseの中の入れ子
+		isError:  true,
+	}, {
+		template: "  {{/* コメントのみ */}}  ", // 出力が空
+		isError:  true,
+	}}
+
+	for _, current := range testCases {
+		actual, err := renderReminderTemplate(current.template, data)
+		if (err != nil) != current.isError {
+			t.Errorf("Illegal return value. template:%v, err:%v", current.template, err)
+		}
+		if actual != current.expected {
+			t.Errorf("Illegal return value. template:%v, returnd:%v", current.template, actual)
+		}
+	}
+
+	// テンプレートが長すぎる
+	if _, err := renderReminderTemplate(strings.Repeat("電", maxReminderTemplateLength+1), data); err == nil {
+		t.Errorf("Too long template was accepted")
+	}
+}
+
+/**
+ * テンプレートを適用したテキストのバブル（フッタにはカードと同じ調整さんへのボタン）
+ */
+func TestConstructTemplateBubble(t *testing.T) {
+	s := sampleSchedule()
+	hash := "3f7ffd73ba174332ae05bd363eba8e71"
+	actual := s.constructTemplateBubble(langJa, hash, "12/24 ○2名 ×1名")
+
+	if actual.Body == nil || len(actual.Body.Contents) != 1 || actual.Body.Contents[0].Text != "12/24 ○2名 ×1名" {
+		t.Errorf("Unmatch body: %+v", actual.Body)
+	}
+	expectedFooter := s.constructAnswerFooter(langJa, hash)
+	if actual.Footer == nil || !reflect.DeepEqual(*actual.Footer, expectedFooter) {
+		t.Errorf("Unmatch footer: %+v", actual.Footer)
+	}
+}
+
+/**
+ * テンプレートを検証、適用できなかったエラーの文言（言語に合わせる）
+ */
+func TestTemplateErrorMessage(t *testing.T) {
+	sample := sampleSchedule()
+	data := sample.reminderTemplateData("3f7ffd73ba174332ae05bd363eba8e71")
+
+	type testParameter struct {
+		lang     string
+		template string
+		expected string
+	}
+	testCases := []testParameter{{
+		lang:     langJa,
+		template: `{{if .Present}}{{template "reminder"}}{{end}}`,
+		expected: "{{template}}は使えません",
+	}, {
+		lang:     langEn,
+		template: `{{if .Present}}{{template "reminder"}}{{end}}`,
+		expected: "{{template}} is not allowed",
+	}, {
+		lang:     langEn,
+		template: "{{range .Names}}{{range $.Names}}{{range $.Names}}{{end}}{{end}}{{end}}",
+		expected: "{{range}} and {{with}} can be nested up to 2 levels",
+	}, {
+		lang:     langEn,
+		template: "{{range .Names}}{{printf \"%9999s\" .}}{{end}}",
+		expected: "The output is too long",
+	}, {
+		lang:     langEn,
+		template: "  ",
+		expected: "The output is empty",
+	}}
+
+	for _, current := range testCases {
+		_, err := renderReminderTemplate(current.template, data)
+		if err == nil {
+			t.Fatalf("Template was accepted. template:%v", current.template)
+		}
+		if actual := templateErrorMessage(current.lang, err); actual != current.expected {
+			t.Errorf("Illegal return value. template:%v, returnd:%v", current.template, actual)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+
+	"golang.org/x/net/context"
+
+	"github.com/line/line-bot-sdk-go/linebot"
+
+	"google.golang.org/appengine"
+	"google.golang.org/appengine/log"
+	"google.golang.org/appengine/urlfetch"
+	"google.golang.org/appengine/user"
+)
+
+// リッチメニューの定義ファイルと画像ファイル
+//
+// 定義を変更したら、nameのバージョン番号を上げること（画像の変更は比較できないため、画像のみ変更した場合も上げる）
+const (
+	richMenuFile      = "templates/richmenu.json"
+	richMenuImageFile = "img/richmenu.png"
+)
+
+/**
+ * リッチメニューの定義ファイルを読み込んで返す
+ */
+func loadRichMenu(path string) (*linebot.RichMenu, error) {
+	body, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var menu linebot.RichMenu
+	if err = json.Unmarshal(body, &menu); err != nil {
+		return nil, err
+	}
+	return &menu, nil
+}
+
+/**
+ * 現在のリッチメニューと定義ファイルの差分を、1項目1行で返す。差分がなければ空
+ *
+ * 現在のリッチメニューがなければ（nil）、新規作成として扱う
+ */
+func diffRichMenu(current *linebot.RichMenu, desired *linebot.RichMenu) []string {
+	if current == nil {
+		return []string{"(new) " + desired.Name}
+	}
+
+	diffs := []string{}
+	diff := func(field string, before interface{}, after interface{}) {
+		if b, a := fmt.Sprintf("%+v", before), fmt.Sprintf("%+v", after); b != a {
+			diffs = append(diffs, field+": "+b+" -> "+a)
+		}
+	}
+	diff("name", current.Name, desired.Name)
+	diff("chatBarText", current.ChatBarText, desired.ChatBarText)
+	diff("size", current.Size, desired.Size)
+	diff("selected", current.Selected, desired.Selected)
+	diff("areas", len(current.Areas), len(desired.Areas))
+	for i := 0; i < len(current.Areas) && i < len(desired.Areas); i++ {
+		diff("areas["+strconv.Itoa(i)+"].bounds", current.Areas[i].Bounds, desired.Areas[i].Bounds)
+		diff("areas["+strconv.Itoa(i)+"].action", current.Areas[i].Action, desired.Areas[i].Action)
+	}
+	return diffs
+}
+
+/**
+ * 現在のデフォルトのリッチメニューを返す。設定されていなければnil
+ */
+func getDefaultRichMenu(c context.Context, bot *linebot.Client) (string, *linebot.RichMenu) {
+	res, err := bot.GetDefaultRichMenu().Do()
+	if err != nil {
+		log.Infof(c, "Default rich menu was not found. err: %v", err)
+		return "", nil
+	}
+	menu, err := bot.GetRichMenu(res.RichMenuID).Do()
+	if err != nil {
+		log.Warningf(c, "Error occurred at get rich menu. id:%v, err: %v", res.RichMenuID, err)
+		return res.RichMenuID, nil
+	}
+	return res.RichMenuID, &linebot.RichMenu{
+		Size:        menu.Size,
+		Selected:    menu.Selected,
+		Name:        menu.Name,
+		ChatBarText: menu.ChatBarText,
+		Areas:       menu.Areas,
+	}
+}
+
+/**
+ * 定義ファイルのリッチメニューを作成して、1:1トークのデフォルトに設定する
+ *
+ * 画像のアップロード、デフォルトへの設定のいずれかに失敗した場合は、作成したリッチメニューを削除する
+ */
+func applyRichMenu(c context.Context, bot *linebot.Client, menu *linebot.RichMenu) (string, error) {
+	res, err := bot.CreateRichMenu(*menu).Do()
+	if err != nil {
+		log.Errorf(c, "Error occurred at create rich menu. err: %v", err)
+		return "", err
+	}
+	id := res.RichMenuID
+
+	if _, err = bot.UploadRichMenuImage(id, richMenuImageFile).Do(); err == nil {
+		_, err = bot.SetDefaultRichMenu(id).Do()
+	}
+	if err != nil {
+		log.Errorf(c, "Error occurred at setup rich menu. id:%v, err: %v", id, err)
+		if _, derr := bot.DeleteRichMenu(id).Do(); derr != nil {
+			log.Warningf(c, "Error occurred at delete rich menu. id:%v, err: %v", id, derr)
+		}
+		return "", err
+	}
+	return id, nil
+}
+
+/**
+ * リッチメニューのプロビジョニング
+ *
+ * 現在のデフォルトのリッチメニューと定義ファイルの差分を表示する。POSTで`apply=true`を指定すると、差分があれば適用する
+ * 管理者以外は403、GETで`apply=true`を指定した場合は405、適用する画像ファイルがなければ何もせずに500を返す
+ *
+ * 引数にContextとhttp.Clientを取るインナーメソッド
+ */
+func richMenuWithContext(c context.Context, client *http.Client, w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+
+	// app.yamlの`login: admin`に頼らず、管理者であることを確認する
+	if !user.IsAdmin(c) {
+		log.Warningf(c, "Rich menu was requested by non-admin user.")
+		http.Error(w, "admin only", http.StatusForbidden)
+		return
+	}
+	apply := r.FormValue("apply") == "true"
+	if apply && r.Method != http.MethodPost {
+		http.Error(w, "apply=true requires POST", http.StatusMethodNotAllowed)
+		return
+	}
+	if apply {
+		if _, err := os.Stat(richMenuImageFile); err != nil {
+			log.Errorf(c, "Rich menu image was not found. path:%v, err: %v", richMenuImageFile, err)
+			http.Error(w, "rich menu image was not found: "+richMenuImageFile+" (place a 2500x843 PNG before applying)", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	desired, err := loadRichMenu(richMenuFile)
+	if err != nil {
+		log.Errorf(c, "Error occurred at load rich menu. err: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	bot, err := createBotClient(c, client)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	currentID, current := getDefaultRichMenu(c, bot)
+	diffs := diffRichMenu(current, desired)
+	fmt.Fprintf(w, "current: %v\n", currentID)
+	if len(diffs) == 0 {
+		fmt.Fprintln(w, "no changes")
+		return
+	}
+	for _, v := range diffs {
+		fmt.Fprintln(w, v)
+	}
+
+	if !apply {
+		fmt.Fprintln(w, "dry run (POST with apply=true to apply)")
+		return
+	}
+
+	id, err := applyRichMenu(c, bot, desired)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	log.Infof(c, "Apply rich menu! name:%v id:%v", desired.Name, id)
+	fmt.Fprintf(w, "applied: %v\n", id)
+
+	// 置き換えた古いリッチメニューは削除する
+	if len(currentID) > 0 {
+		if _, err = bot.DeleteRichMenu(currentID).Do(); err != nil {
+			log.Warningf(c, "Error occurred at delete old rich menu. id:%v, err: %v", currentID, err)
+		}
+	}
+}
+
+/**
+ * リッチメニューのプロビジョニング（管理者のみアクセス可）
+ */
+func richMenu(w http.ResponseWriter, r *http.Request) {
+	c := appengine.NewContext(r)
+	richMenuWithContext(c, urlfetch.Client(c), w, r)
+}
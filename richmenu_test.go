@@ -0,0 +1,113 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/line/line-bot-sdk-go/linebot"
+	"github.com/thingful/httpmock"
+	"google.golang.org/appengine"
+	"google.golang.org/appengine/aetest"
+	"google.golang.org/appengine/urlfetch"
+)
+
+/**
+ * リッチメニューの定義ファイルが、サイズ内に収まり、有効なコマンドを実行すること
+ */
+func TestLoadRichMenu(t *testing.T) {
+	menu, err := loadRichMenu(richMenuFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(menu.Areas) != 4 {
+		t.Errorf("Unmatch areas count: %v", len(menu.Areas))
+	}
+	for i, area := range menu.Areas {
+		b := area.Bounds
+		if b.X < 0 || b.Y < 0 || b.X+b.Width > menu.Size.Width || b.Y+b.Height > menu.Size.Height {
+			t.Errorf("Area is out of bounds. index:%v bounds:%+v", i, b)
+		}
+		if _, err := parsePostbackData(area.Action.Data); err != nil {
+			t.Errorf("Invalid postback data. index:%v data:%v err:%v", i, area.Action.Data, err)
+		}
+	}
+}
+
+/**
+ * 現在のリッチメニューと定義ファイルの差分
+ */
+func TestDiffRichMenu(t *testing.T) {
+	desired, err := loadRichMenu(richMenuFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 現在のリッチメニューがなければ新規作成
+	if actual := diffRichMenu(nil, desired); len(actual) != 1 {
+		t.Errorf("Unmatch diffs (new): %v", actual)
+	}
+
+	// 同じ定義であれば差分なし
+	same, _ := loadRichMenu(richMenuFile)
+	if actual := diffRichMenu(same, desired); len(actual) != 0 {
+		t.Errorf("Unmatch diffs (same): %v", actual)
+	}
+
+	// 変更された項目のみ差分になる
+	current, _ := loadRichMenu(richMenuFile)
+	current.Name = "chouseisan-reminder-v0"
+	current.Areas[1].Action = linebot.RichMenuAction{Type: linebot.RichMenuActionTypeMessage, Text: "/remind now"}
+	actual := diffRichMenu(current, desired)
+	if len(actual) != 2 || actual[0] != "name: chouseisan-reminder-v0 -> "+desired.Name {
+		t.Errorf("Unmatch diffs (changed): %v", actual)
+	}
+}
+
+/**
+ * リッチメニューのプロビジョニングは、管理者のみ、適用はPOSTのみ受け付ける
+ */
+func TestRichMenuWithContextRejected(t *testing.T) {
+	instance, err := aetest.NewInstance(nil)
+	if err != nil {
+		t.Fatalf("Failed to create aetest instance: %v", err)
+	}
+	defer instance.Close()
+
+	type testParameter struct {
+		method   string
+		admin    bool
+		expected int
+	}
+	testCases := []testParameter{{
+		method:   "POST", // 管理者以外
+		admin:    false,
+		expected: http.StatusForbidden,
+	}, {
+		method:   "GET", // GETでの適用
+		admin:    true,
+		expected: http.StatusMethodNotAllowed,
+	}}
+
+	for _, current := range testCases {
+		req, err := instance.NewRequest(current.method, "/admin/richmenu?apply=true", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if current.admin {
+			req.Header.Set("X-AppEngine-User-Is-Admin", "1")
+		}
+		c := appengine.NewContext(req)
+
+		// LINEへのリクエストはしない（モックのスタブを登録しない）
+		client := urlfetch.Client(c)
+		httpmock.ActivateNonDefault(client)
+
+		res := httptest.NewRecorder()
+		richMenuWithContext(c, client, res, req)
+		httpmock.DeactivateAndReset()
+		if res.Code != current.expected {
+			t.Errorf("Unmatch status code. method:%v admin:%v, returnd:%v", current.method, current.admin, res.Code)
+		}
+	}
+}